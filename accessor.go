@@ -3,24 +3,65 @@ package access
 import (
 	"fmt"
 	"reflect"
-	"regexp"
 	"strconv"
 	"strings"
 )
 
-const pathRegexStr = `(?i)(?P<field>\w+)?(?P<index>\[\d+\])?(?P<dot>\.?)`
-
-var pathRegex = regexp.MustCompile(pathRegexStr)
-
 var (
 	pathReaderInterface = reflect.TypeOf((*PathReader)(nil)).Elem()
 	pathWriterInterface = reflect.TypeOf((*PathWriter)(nil)).Elem()
 )
 
+// ParseError describes a selector which could not be parsed into a Path.
+type ParseError struct {
+	// Input is the selector being parsed
+	Input string
+	// Offset is the byte offset in Input where parsing failed
+	Offset int
+	// Expected is the token which was expected at Offset
+	Expected string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("Parsing error `%s` at %d: %s expected", e.Input, e.Offset, e.Expected)
+}
+
+// New builds a Path from v just like Parse, but panics with the *ParseError if v can't be parsed.
 func New(v interface{}) Path {
+	p, err := Parse(v)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Parse builds a Path from a selector string like `field[0].key`, an integer index, a fmt.Stringer or a Path, which is copied.
+// Parsed selectors are cached, see SetPathCacheSize.
+func Parse(v interface{}) (Path, error) {
+
+	p, err := parseCached(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// the cached path is shared, so it's copied before the caller can modify it
+	return append(Path{}, p...), nil
+}
+
+// selectorPath is Parse returning the cached path, which must not be modified.
+func selectorPath(v interface{}) (Path, error) {
+	return parseCached(v)
+}
+
+// parse builds a Path from v without using the cache.
+func parse(v interface{}) (Path, error) {
 
 	var str string
 
+	if p, ok := v.(Path); ok {
+		return append(Path{}, p...), nil
+	}
+
 	if s, ok := v.(fmt.Stringer); ok {
 		str = s.String()
 	} else {
@@ -30,20 +71,14 @@ func New(v interface{}) Path {
 		case string:
 			str = s
 		default:
-			panic(fmt.Sprintf("Can't build path from %#v", v))
+			return nil, &ParseError{fmt.Sprintf("%#v", v), 0, "string, integer or fmt.Stringer"}
 		}
 	}
 	str = strings.TrimSpace(str)
 	selector := str
 
-	defer func() {
-		if r := recover(); r != nil {
-			panic(fmt.Sprintf("Parsing error `%s` at `%s`: %s ", str, selector, r))
-		}
-	}()
-
 	if len(selector) == 0 {
-		return Path{}
+		return Path{}, nil
 	}
 
 	parts := []interface{}{}
@@ -52,7 +87,12 @@ func New(v interface{}) Path {
 		dot           bool
 		field         string
 		index         string
+		key           string
+		quoted        bool
+		filter        bool
 		fieldExpected bool
+		descent       bool
+		offset        int
 	)
 
 	if selector[0:1] != "[" {
@@ -61,68 +101,112 @@ func New(v interface{}) Path {
 
 	for len(selector) > 0 {
 
-		match := pathRegex.FindStringSubmatch(selector)
-		if len(match) == 0 {
-			panic("Doesn't match selector")
-		}
-
-		dot = false
-		field = ""
-		index = ""
-
-		for i, name := range pathRegex.SubexpNames() {
-
-			if i == 0 || match[i] == "" {
-				continue
+		// recursive descent is either leading `..` or a second dot after the previous segment
+		if (dot && selector[0] == '.') || (offset == 0 && strings.HasPrefix(selector, "..")) {
+			n := 2
+			if dot {
+				n = 1
 			}
 
-			if name == "field" {
-				field = match[i]
-				continue
-			}
+			parts = append(parts, Descent{})
+			offset += n
+			selector = selector[n:]
 
-			if name == "index" {
-				index = match[i][1 : len(match[i])-1] // get value from [%v]
-				continue
+			if len(selector) == 0 {
+				return nil, &ParseError{str, offset, "field or index"}
 			}
 
-			if name == "dot" {
-				dot = true
-				continue
-			}
+			dot = false
+			descent = true
+			continue
 		}
 
-		if field == "" && fieldExpected {
-			panic("field expected")
+		seg, consumed := scanSegment(selector)
+
+		dot = seg.dot
+		field = seg.field
+		index = seg.index
+		key = seg.key
+		quoted = seg.quoted
+		filter = seg.filter
+
+		if field == "" && fieldExpected && !descent {
+			return nil, &ParseError{str, offset, "field"}
 		}
 
-		if field != "" && !fieldExpected {
-			panic("field not expected")
+		if field != "" && !fieldExpected && !descent {
+			return nil, &ParseError{str, offset, "dot or index"}
 		}
 
-		if field == "" && index == "" {
-			panic("field or index expected")
+		if field == "" && index == "" && !quoted && !filter {
+			return nil, &ParseError{str, offset, "field or index"}
 		}
 
-		if field != "" {
+		if field == "*" {
+			parts = append(parts, Wildcard{})
+		} else if field != "" {
 			parts = append(parts, field)
 		}
 
-		if index != "" {
+		if index == "*" {
+			parts = append(parts, Wildcard{})
+		} else if index == "-" {
+			parts = append(parts, Append{})
+		} else if strings.Contains(index, ":") {
+			r, err := parseRange(index)
+			if err != nil {
+				return nil, &ParseError{str, offset + len(field) + 1, "numeric range"}
+			}
+			parts = append(parts, r)
+		} else if index != "" {
 			ni, err := strconv.Atoi(index)
 			if err != nil {
-				panic("numeric index expected")
+				return nil, &ParseError{str, offset + len(field) + 1, "numeric index"}
 			}
 			parts = append(parts, ni)
 		}
 
-		selector = strings.Replace(selector, match[0], "", 1)
+		if quoted {
+			k, err := unquoteKey(key)
+			if err != nil {
+				return nil, &ParseError{str, offset + len(field) + 1, "valid escape sequence"}
+			}
+			parts = append(parts, k)
+		}
+
+		if filter {
+			start := offset + len(field) + 2 // skip `[?`
+
+			expr, n, ok := scanFilter(str[start:])
+			if !ok {
+				return nil, &ParseError{str, start, "closing bracket"}
+			}
+
+			f, err := ParseFilter(expr)
+			if err != nil {
+				if e, ok := err.(*ParseError); ok {
+					return nil, &ParseError{str, start + e.Offset, e.Expected}
+				}
+				return nil, err
+			}
+			parts = append(parts, f)
+
+			consumed = len(field) + 2 + n
+			dot = strings.HasPrefix(selector[consumed:], ".")
+			if dot {
+				consumed++
+			}
+		}
+
+		offset += consumed
+		selector = selector[consumed:]
 
 		fieldExpected = false
+		descent = false
 
 		if dot {
 			if len(selector) == 0 {
-				panic("Unexpected dot")
+				return nil, &ParseError{str, offset, "field"}
 			}
 
 			fieldExpected = true
@@ -130,7 +214,150 @@ func New(v interface{}) Path {
 
 	}
 
-	return Path(parts)
+	return Path(parts), nil
+}
+
+// pathSegment is a selector segment: an optional field or `*`, followed by an optional index,
+// filter start or quoted key in brackets and an optional dot.
+type pathSegment struct {
+	field  string
+	index  string // value from [%v]
+	key    string // value from [%q] including quotes
+	quoted bool
+	filter bool
+	dot    bool
+}
+
+// scanSegment matches the segment at start of s, returning it together with number of consumed bytes.
+// Brackets which don't hold a valid index, filter start or quoted key are left unconsumed.
+func scanSegment(s string) (pathSegment, int) {
+
+	var seg pathSegment
+
+	n := 0
+	for n < len(s) && isWordByte(s[n]) {
+		n++
+	}
+	if n == 0 && len(s) > 0 && s[0] == '*' {
+		n = 1
+	}
+	seg.field = s[:n]
+
+	if n < len(s) && s[n] == '[' {
+		rest := s[n+1:]
+
+		switch {
+		case strings.HasPrefix(rest, "?"):
+			seg.filter = true
+			n += 2
+		case strings.HasPrefix(rest, `"`) || strings.HasPrefix(rest, "'"):
+			if l := scanQuoted(rest); l > 0 && l < len(rest) && rest[l] == ']' {
+				seg.key = rest[:l]
+				seg.quoted = true
+				n += l + 2
+			}
+		default:
+			if l := strings.IndexByte(rest, ']'); l >= 0 && isIndex(rest[:l]) {
+				seg.index = rest[:l]
+				n += l + 2
+			}
+		}
+	}
+
+	if n < len(s) && s[n] == '.' {
+		seg.dot = true
+		n++
+	}
+
+	return seg, n
+}
+
+// scanQuoted returns length of the string quoted by the first byte of s including quotes, or 0 if it isn't closed.
+func scanQuoted(s string) int {
+	q := s[0]
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case q:
+			return i + 1
+		case '\\':
+			// any escaped character but a line break
+			if i+1 >= len(s) || s[i+1] == '\n' {
+				return 0
+			}
+			i++
+		}
+	}
+	return 0
+}
+
+// isIndex reports whether s is a number, range, `-` or `*` allowed in brackets.
+func isIndex(s string) bool {
+
+	if s == "-" || s == "*" {
+		return true
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return false
+	}
+
+	for _, part := range parts {
+		part = strings.TrimPrefix(part, "-")
+		for i := 0; i < len(part); i++ {
+			if part[i] < '0' || part[i] > '9' {
+				return false
+			}
+		}
+		// a single number needs a digit, while range bounds may be omitted
+		if len(parts) == 1 && len(part) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isWordByte reports whether c is an ASCII letter, digit or underscore.
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_'
+}
+
+// isWord reports whether s is a field which can be written without brackets.
+func isWord(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isWordByte(s[i]) {
+			return false
+		}
+	}
+	return len(s) > 0
+}
+
+// unquoteKey decodes bracketed map key written either in double or single quotes.
+func unquoteKey(s string) (string, error) {
+	if s[0] == '\'' {
+		// rewrite into double quoted form understood by strconv.Unquote
+		var b strings.Builder
+		b.WriteByte('"')
+		for i := 1; i < len(s)-1; i++ {
+			switch c := s[i]; {
+			case c == '\\' && s[i+1] == '\'':
+				b.WriteByte('\'')
+				i++
+			case c == '\\':
+				b.WriteByte(c)
+				b.WriteByte(s[i+1])
+				i++
+			case c == '"':
+				b.WriteString(`\"`)
+			default:
+				b.WriteByte(c)
+			}
+		}
+		b.WriteByte('"')
+		s = b.String()
+	}
+	return strconv.Unquote(s)
 }
 
 type Path []interface{}
@@ -140,32 +367,52 @@ func (p Path) String() string {
 	for i, accessor := range p {
 		switch s := accessor.(type) {
 		case string:
-			if i != 0 {
-				path += "." + s
-			} else {
+			if !isWord(s) {
+				path += "[" + strconv.Quote(s) + "]"
+			} else if i == 0 || p[i-1] == (Descent{}) {
 				path += s
+			} else {
+				path += "." + s
 			}
 
 		case int:
 			path += fmt.Sprintf("[%d]", s)
+		case Append:
+			path += "[-]"
+		case Wildcard:
+			path += "[*]"
+		case Descent:
+			path += ".."
+		case Range:
+			path += "[" + s.String() + "]"
+		case Filter:
+			path += "[?" + s.Expr + "]"
 		}
 	}
 
 	return path
 }
 
-func (p Path) write(v reflect.Value, w reflect.Value, wt reflect.Type) (err error) {
+func (p Path) write(a *Accessor, v reflect.Value, w reflect.Value, wt reflect.Type) (err error) {
 
 	if !v.CanAddr() {
-		return Error{fmt.Errorf("Got unadressable value"), []interface{}{}}
+		return Error{kindError{fmt.Errorf("Got unadressable value"), ErrNotAddressable}, []interface{}{}}
 	}
 
 	if len(p) == 0 {
-		return indirectWrite(v, w,wt)
+		return indirectWrite(a, v, w,wt)
 	}
 
+	// custom writers get a copy, as p may be shared by the selector cache
 	if writer, ok := indirectRead(v, pathWriterInterface).Interface().(PathWriter); ok {
-		return writer.WritePath(p, w.Interface())
+		var val interface{}
+		if w.IsValid() {
+			val = w.Interface()
+		}
+		if aw, ok := writer.(AccessorPathWriter); ok {
+			return aw.WritePathWith(a, append(Path{}, p...), val)
+		}
+		return writer.WritePath(append(Path{}, p...), val)
 	}
 
 	var rpath *Path
@@ -177,9 +424,13 @@ func (p Path) write(v reflect.Value, w reflect.Value, wt reflect.Type) (err erro
 
 	switch s := p[0].(type) {
 	case string:
-		err = writeField(v, s, rpath, w, wt)
+		err = writeField(a, v, s, rpath, w, wt)
 	case int:
-		err = writeIndex(v, s, rpath, w, wt)
+		err = writeIndex(a, v, s, rpath, w, wt)
+	case Append:
+		err = writeAppend(a, v, rpath, w, wt)
+	case Range:
+		err = writeRange(a, v, s, rpath, w, wt)
 	}
 
 	if err != nil {
@@ -195,14 +446,20 @@ func (p Path) write(v reflect.Value, w reflect.Value, wt reflect.Type) (err erro
 	return err
 }
 
-func (p Path) read(v reflect.Value) (rv reflect.Value, err error) {
+func (p Path) read(a *Accessor, v reflect.Value) (rv reflect.Value, err error) {
 
 	if len(p) == 0 {
 		return v, nil
 	}
 
+	// custom readers get a copy, as p may be shared by the selector cache
 	if reader, ok := indirectRead(v, pathReaderInterface).Interface().(PathReader); ok {
-		val, err := reader.ReadPath(p)
+		var val interface{}
+		if ar, ok := reader.(AccessorPathReader); ok {
+			val, err = ar.ReadPathWith(a, append(Path{}, p...))
+		} else {
+			val, err = reader.ReadPath(append(Path{}, p...))
+		}
 		return reflect.ValueOf(val), err
 	}
 
@@ -215,9 +472,19 @@ func (p Path) read(v reflect.Value) (rv reflect.Value, err error) {
 
 	switch s := p[0].(type) {
 	case string:
-		rv, err = readField(v, s, rpath)
+		rv, err = readField(a, v, s, rpath)
 	case int:
-		rv, err = readIndex(v, s, rpath)
+		rv, err = readIndex(a, v, s, rpath)
+	case Append:
+		err = fmt.Errorf("Append index `[-]` can only be written")
+	case Range:
+		rv, err = readRange(a, v, s, rpath)
+	case Wildcard:
+		err = fmt.Errorf("Wildcard `[*]` can only be read with ReadAll")
+	case Descent:
+		err = fmt.Errorf("Recursive descent `..` can only be read with ReadAll")
+	case Filter:
+		err = fmt.Errorf("Filter `[?%s]` can only be read with ReadAll", s.Expr)
 	}
 
 	if err != nil {
@@ -234,21 +501,46 @@ func (p Path) read(v reflect.Value) (rv reflect.Value, err error) {
 }
 
 func (path Path) Write(v interface{}, w interface{}) error {
+	return defaultAccessor().writePath(path, v, w)
+}
+
+func (a *Accessor) writePath(path Path, v interface{}, w interface{}) error {
+
+	if err := a.checkDepth(path); err != nil {
+		return err
+	}
 
 	rv := reflect.ValueOf(v)
 
 	if rv.Kind() != reflect.Ptr {
-		return Error{fmt.Errorf("Non pointer value"), []interface{}{}}
+		return Error{kindError{fmt.Errorf("Non pointer value"), ErrNotAddressable}, []interface{}{}}
 	}
 
-	return path.write(rv.Elem(), reflect.ValueOf(w), reflect.TypeOf(w))
+	if path.multiIndex() < 0 {
+		return path.write(a, rv.Elem(), reflect.ValueOf(w), reflect.TypeOf(w))
+	}
+
+	return path.writeAll(a, rv.Elem(), reflect.ValueOf(w), reflect.TypeOf(w))
 }
 
+// Read reads value at path. Paths which may match several locations fail, they are read by ReadAll.
 func (path Path) Read(v interface{}) (interface{}, error) {
+	return defaultAccessor().readPath(path, v)
+}
+
+func (a *Accessor) readPath(path Path, v interface{}) (interface{}, error) {
+
+	if err := a.checkDepth(path); err != nil {
+		return nil, err
+	}
+
+	if err := path.checkSingle(); err != nil {
+		return nil, err
+	}
 
 	rv := reflect.ValueOf(v)
 
-	re, err := path.read(rv)
+	re, err := path.read(a, rv)
 
 	if err != nil || !re.IsValid() {
 		return nil, err
@@ -258,6 +550,10 @@ func (path Path) Read(v interface{}) (interface{}, error) {
 }
 
 func (path Path) MustRead(v interface{}, dv ...interface{}) (value interface{}) {
+	return defaultAccessor().mustReadPath(path, v, dv...)
+}
+
+func (a *Accessor) mustReadPath(path Path, v interface{}, dv ...interface{}) (value interface{}) {
 	var dval interface{}
 	if len(dv) == 1 {
 		dval = dv[0]
@@ -269,7 +565,7 @@ func (path Path) MustRead(v interface{}, dv ...interface{}) (value interface{})
 		}
 	}()
 
-	t, err := path.Read(v)
+	t, err := a.readPath(path, v)
 
 	if err != nil || (t == nil && dval != nil) {
 		return dval
@@ -287,6 +583,11 @@ func (e Error) Error() string {
 	return fmt.Sprintf("%s at `%s`", e.error, Path(e.Path))
 }
 
+// Unwrap returns the underlying error, so that errors.Is matches kinds like ErrNotFound.
+func (e Error) Unwrap() error {
+	return e.error
+}
+
 func (e Error) back(s interface{}) Error {
 	e.Path = append([]interface{}{s}, e.Path...)
 	return e
@@ -301,20 +602,32 @@ type PathWriter interface {
 	WritePath(Path, interface{}) error
 }
 
+// AccessorPathReader is a PathReader which reads nested values with the Accessor reading it.
+type AccessorPathReader interface {
+	PathReader
+	ReadPathWith(*Accessor, Path) (interface{}, error)
+}
+
+// AccessorPathWriter is a PathWriter which writes nested values with the Accessor writing it.
+type AccessorPathWriter interface {
+	PathWriter
+	WritePathWith(*Accessor, Path, interface{}) error
+}
+
 func Write(s interface{}, v interface{}, val interface{}) error {
-	return New(s).Write(v, val)
+	return defaultAccessor().Write(s, v, val)
 }
 
 func Read(s interface{}, v interface{}) (interface{}, error) {
-	return New(s).Read(v)
+	return defaultAccessor().Read(s, v)
 }
 
 func MustRead(s interface{}, v interface{}, dv ...interface{}) (value interface{}) {
-	return New(s).MustRead(v, dv...)
+	return defaultAccessor().MustRead(s, v, dv...)
 }
 
 //set value allocating pointers if needed
-func indirectWrite(v reflect.Value, w reflect.Value,wt reflect.Type) (err error) {
+func indirectWrite(a *Accessor, v reflect.Value, w reflect.Value,wt reflect.Type) (err error) {
 
 	nilValue:=(wt == nil)
 
@@ -345,6 +658,10 @@ func indirectWrite(v reflect.Value, w reflect.Value,wt reflect.Type) (err error)
 		}
 
 		if v.IsNil() {
+			if !a.allocate {
+				return allocationError(v.Type())
+			}
+
 			nv := reflect.New(v.Type().Elem())
 
 			defer func(v, nv reflect.Value) {
@@ -361,7 +678,7 @@ func indirectWrite(v reflect.Value, w reflect.Value,wt reflect.Type) (err error)
 
 
 	if !v.CanSet() {
-		return fmt.Errorf("got value that couldn't be changed")
+		return kindError{fmt.Errorf("got value that couldn't be changed"), ErrNotAddressable}
 	}
 
 	if nilValue {
@@ -370,11 +687,18 @@ func indirectWrite(v reflect.Value, w reflect.Value,wt reflect.Type) (err error)
 			v.Set(reflect.Zero(v.Type()))
 			return nil
 		}
-		return fmt.Errorf("value must be nullable like interface,pointer,map or slice")
+		return kindError{fmt.Errorf("value must be nullable like interface,pointer,map or slice"), ErrTypeMismatch}
 	}
 
 	if !wt.AssignableTo(v.Type()) {
-		return fmt.Errorf("can't assign")
+		c := a.converter
+		if c == nil {
+			return kindError{fmt.Errorf("can't assign"), ErrTypeMismatch}
+		}
+
+		if w, err = c.convert(w, v.Type()); err != nil {
+			return err
+		}
 	}
 
 	v.Set(w)
@@ -392,7 +716,7 @@ func indirectRead(v reflect.Value, accessor reflect.Type) reflect.Value {
 
 	for {
 
-		if accessor != nil && v.Type().NumMethod() > 0 && v.Type().Implements(accessor) {
+		if accessor != nil && implements(v.Type(), accessor) {
 			break
 		}
 
@@ -407,7 +731,7 @@ func indirectRead(v reflect.Value, accessor reflect.Type) reflect.Value {
 			}
 		}
 
-		if v.Kind() != reflect.Ptr {
+		if v.Kind() != reflect.Ptr || v.IsNil() {
 			break
 		}
 
@@ -2,6 +2,7 @@ package access
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -21,6 +22,92 @@ func TestPropertyPath(t *testing.T) {
 	assert.Equal("field[0][1].key[2]", New("field[0][1].key[2]").String())
 }
 
+func TestQuotedKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(Path{"headers", "Content-Type"}, New(`headers["Content-Type"]`))
+	assert.Equal(Path{"m", "a.b", "c"}, New(`m['a.b'].c`))
+	assert.Equal(Path{"a b", 0}, New(`["a b"][0]`))
+	assert.Equal(Path{"m", `say "hi"`}, New(`m["say \"hi\""]`))
+	assert.Equal(Path{"m", `it's`}, New(`m['it\'s']`))
+	assert.Equal(Path{"m", `"q"`}, New(`m['"q"']`))
+	assert.Equal(Path{"m", "[x]"}, New(`m["[x]"]`))
+	assert.Equal(Path{"m", ""}, New(`m[""]`))
+
+	assert.Panics(func() { New(`m["a`) })
+	assert.Panics(func() { New(`m['a"]`) })
+	assert.Panics(func() { New(`m["a"]b`) })
+	assert.Panics(func() { New(`m["\q"]`) })
+
+	for _, p := range []Path{
+		{"headers", "Content-Type"},
+		{"a.b", "c", 1, "d e"},
+		{"m", `"quoted" \ 'single'`},
+		{"m", "", "x"},
+	} {
+		assert.Equal(p, New(p.String()))
+	}
+
+	assert.Equal(`headers["Content-Type"].value`, Path{"headers", "Content-Type", "value"}.String())
+
+	data := map[string]interface{}{
+		"headers": map[string]string{"Content-Type": "text/plain"},
+		"a.b":     map[string]interface{}{"c": 1},
+	}
+
+	assert.Equal("text/plain", MustRead(`headers["Content-Type"]`, data))
+	assert.Equal(1, MustRead(`['a.b'].c`, data))
+
+	assert.NoError(Write(`headers['X-Request-Id']`, &data, "42"))
+	assert.Equal("42", MustRead(`headers["X-Request-Id"]`, data))
+}
+
+func TestParse(t *testing.T) {
+	assert := assert.New(t)
+
+	p, err := Parse("field[0][1].key[2]")
+	assert.NoError(err)
+	assert.Equal(Path{"field", 0, 1, "key", 2}, p)
+
+	p, err = Parse(3)
+	assert.NoError(err)
+	assert.Equal(Path{3}, p)
+
+	cases := []struct {
+		Selector interface{}
+		Offset   int
+		Expected string
+	}{
+		{"f.", 2, "field"},
+		{".f", 0, "field"},
+		{"f[ 1]", 1, "field or index"},
+		{"[1]field", 3, "dot or index"},
+		{"a.b...c", 5, "field or index"},
+		{[]string{"field"}, 0, "string, integer or fmt.Stringer"},
+	}
+
+	for _, c := range cases {
+		p, err := Parse(c.Selector)
+		assert.Nil(p)
+
+		if assert.IsType(&ParseError{}, err) {
+			e := err.(*ParseError)
+			assert.Equal(c.Offset, e.Offset, "%v", c.Selector)
+			assert.Equal(c.Expected, e.Expected, "%v", c.Selector)
+		}
+	}
+
+	defer func() {
+		var e *ParseError
+		if assert.True(errors.As(recover().(error), &e)) {
+			assert.Equal("f.", e.Input)
+			assert.Equal(2, e.Offset)
+		}
+	}()
+
+	New("f.")
+}
+
 type glossary struct {
 	GlossDiv *glossDiv `json:"GlossDiv"`
 	Title    string    `json:"title"`
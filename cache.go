@@ -0,0 +1,216 @@
+package access
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// typeCache memoizes reflection lookups of an Accessor per type,
+// as resolved names depend on its matcher and tags.
+type typeCache struct {
+	structs sync.Map // reflect.Type -> *structTable
+	methods sync.Map // reflect.Type -> *methodTable
+}
+
+// maxResolvedNames bounds number of names remembered per type, as they may come from user input.
+const maxResolvedNames = 256
+
+// resolvedNames remembers indices of names found in a table, names which aren't found aren't remembered.
+type resolvedNames struct {
+	names sync.Map // name or methodKey -> index
+	count atomic.Int32
+}
+
+func (r *resolvedNames) load(key interface{}) (int, bool) {
+	i, ok := r.names.Load(key)
+	if !ok {
+		return 0, false
+	}
+	return i.(int), true
+}
+
+func (r *resolvedNames) store(key interface{}, i int) {
+	if r.count.Add(1) <= maxResolvedNames {
+		r.names.Store(key, i)
+	}
+}
+
+// structTable lists fields of a struct type which could be named in paths.
+type structTable struct {
+	// fields are exported visible fields which aren't hidden by tags
+	fields []reflect.StructField
+	// tagged are indices of fields by names given by tags
+	tagged map[string]int
+	// goNames are indices of fields by Go names
+	goNames map[string]int
+	// elements are direct fields listed by a wildcard
+	elements []elementField
+	resolved resolvedNames
+}
+
+// methodTable lists methods of a type which could be named in paths.
+type methodTable struct {
+	names    []string
+	indices  map[string]int
+	resolved resolvedNames
+}
+
+type methodKey struct {
+	name   string
+	prefix string
+}
+
+// elementField is a struct field listed by a wildcard.
+type elementField struct {
+	name  string
+	index int
+}
+
+type implementsKey struct {
+	t, iface reflect.Type
+}
+
+// implementsCache memoizes which types implement accessor interfaces.
+var implementsCache sync.Map // implementsKey -> bool
+
+func implements(t reflect.Type, iface reflect.Type) bool {
+
+	if t.NumMethod() == 0 {
+		return false
+	}
+
+	key := implementsKey{t, iface}
+
+	if ok, found := implementsCache.Load(key); found {
+		return ok.(bool)
+	}
+
+	ok := t.Implements(iface)
+	implementsCache.Store(key, ok)
+
+	return ok
+}
+
+// newStructTable lists fields of struct type t according to tags of a.
+func newStructTable(a *Accessor, t reflect.Type) *structTable {
+
+	st := &structTable{tagged: map[string]int{}, goNames: map[string]int{}, elements: []elementField{}}
+
+	for _, f := range reflect.VisibleFields(t) {
+		if f.PkgPath != "" {
+			continue
+		}
+
+		name, hidden := fieldName(a, f)
+		if hidden {
+			continue
+		}
+
+		if _, ok := st.tagged[name]; name != "" && !ok {
+			st.tagged[name] = len(st.fields)
+		}
+		if _, ok := st.goNames[f.Name]; !ok {
+			st.goNames[f.Name] = len(st.fields)
+		}
+
+		st.fields = append(st.fields, f)
+
+		if len(f.Index) == 1 {
+			if name == "" {
+				name = f.Name
+			}
+			st.elements = append(st.elements, elementField{name, f.Index[0]})
+		}
+	}
+
+	return st
+}
+
+// newMethodTable lists methods of type t.
+func newMethodTable(t reflect.Type) *methodTable {
+
+	mt := &methodTable{names: make([]string, t.NumMethod()), indices: map[string]int{}}
+
+	for i := range mt.names {
+		mt.names[i] = t.Method(i).Name
+		mt.indices[mt.names[i]] = i
+	}
+
+	return mt
+}
+
+func structTableOf(a *Accessor, t reflect.Type) *structTable {
+
+	if st, ok := a.cache.structs.Load(t); ok {
+		return st.(*structTable)
+	}
+
+	st, _ := a.cache.structs.LoadOrStore(t, newStructTable(a, t))
+
+	return st.(*structTable)
+}
+
+func methodTableOf(a *Accessor, t reflect.Type) *methodTable {
+
+	if mt, ok := a.cache.methods.Load(t); ok {
+		return mt.(*methodTable)
+	}
+
+	mt, _ := a.cache.methods.LoadOrStore(t, newMethodTable(t))
+
+	return mt.(*methodTable)
+}
+
+// structField finds field of struct type t named by a tag or having Go name matched by NameMatcher.
+func structField(a *Accessor, t reflect.Type, name string) (reflect.StructField, bool) {
+
+	st := structTableOf(a, t)
+
+	if i, ok := st.resolved.load(name); ok {
+		return st.fields[i], true
+	}
+
+	i := findStructField(a, st, name)
+	if i < 0 {
+		return reflect.StructField{}, false
+	}
+
+	st.resolved.store(name, i)
+
+	return st.fields[i], true
+}
+
+// structMethodIndex returns index of method of type t named by prefix and Go name matching name, or -1.
+func structMethodIndex(a *Accessor, t reflect.Type, name string, prefix string) int {
+
+	mt := methodTableOf(a, t)
+	key := methodKey{name, prefix}
+
+	if i, ok := mt.resolved.load(key); ok {
+		return i
+	}
+
+	i := findStructMethod(a, mt, name, prefix)
+	if i >= 0 {
+		mt.resolved.store(key, i)
+	}
+
+	return i
+}
+
+// structMethod finds method of v named by prefix and Go name matching name.
+func structMethod(a *Accessor, v reflect.Value, name string, prefix string) reflect.Value {
+
+	i := structMethodIndex(a, v.Type(), name, prefix)
+	if i < 0 {
+		return reflect.Value{}
+	}
+
+	return v.Method(i)
+}
+
+// structElements lists exported fields of struct type t which aren't hidden by tags.
+func structElements(a *Accessor, t reflect.Type) []elementField {
+	return structTableOf(a, t).elements
+}
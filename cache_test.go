@@ -0,0 +1,188 @@
+package access
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type cacheUser struct {
+	UserID  int `json:"uid"`
+	Name    string
+	Address *cacheAddress
+	Tags    []string
+	secret  string
+}
+
+type cacheAddress struct {
+	City string
+}
+
+func (u *cacheUser) Secret() string {
+	return u.secret
+}
+
+func newCacheUser() *cacheUser {
+	return &cacheUser{1, "foo", &cacheAddress{"bar"}, []string{"a", "b"}, "baz"}
+}
+
+func TestTypeCache(t *testing.T) {
+	assert := assert.New(t)
+
+	u := newCacheUser()
+
+	a := NewAccessor(WithTagNames("json"))
+	for i := 0; i < 2; i++ {
+		assert.Equal(1, a.MustRead("uid", u))
+		assert.Equal("bar", a.MustRead("address.city", u))
+		assert.Equal("baz", a.MustRead("secret", u))
+		assert.False(a.Exists("missing", u))
+	}
+
+	// resolved names aren't shared between accessors with different options
+	b := NewAccessor(WithTagNames(), WithNameMatcher(ExactMatcher))
+	assert.False(b.Exists("uid", u))
+	assert.False(b.Exists("address", u))
+	assert.True(b.Exists("UserID", u))
+	assert.True(a.Exists("uid", u))
+
+	func() {
+		Configure(WithTagNames("json"))
+		defer Configure(WithTagNames())
+		assert.True(Exists("uid", u))
+	}()
+	assert.False(Exists("uid", u))
+
+	matches, err := a.ReadAll("*", u)
+	assert.NoError(err)
+	assert.Len(matches, 4)
+	assert.Equal(Path{"uid"}, matches[0].Path)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := NewAccessor(WithTagNames("json"))
+			for j := 0; j < 100; j++ {
+				assert.Equal("bar", c.MustRead("address.city", u))
+				assert.Equal("baz", c.MustRead("secret", u))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestTypeCacheBounded(t *testing.T) {
+	assert := assert.New(t)
+
+	type tagged struct {
+		Name   string `json:"n"`
+		secret string `access:"s"`
+	}
+
+	a := NewAccessor(WithTagNames("access", "json"))
+	v := &tagged{"foo", "bar"}
+
+	// unexported fields aren't matched by tags
+	assert.Equal("foo", a.MustRead("n", v))
+	assert.False(a.Exists("s", v))
+
+	count := func(r *resolvedNames) int {
+		n := 0
+		r.names.Range(func(interface{}, interface{}) bool {
+			n++
+			return true
+		})
+		return n
+	}
+
+	st := structTableOf(a, reflect.TypeOf(*v))
+
+	// missing names aren't remembered, found ones are up to maxResolvedNames
+	for i := 0; i < 2*maxResolvedNames; i++ {
+		assert.False(a.Exists(fmt.Sprintf("missing%d", i), v))
+	}
+	assert.Equal(1, count(&st.resolved))
+
+	prefixed := NewAccessor(WithNameMatcher(NameMatcherFunc(strings.HasPrefix)))
+	st = structTableOf(prefixed, reflect.TypeOf(*v))
+
+	for i := 0; i < 2*maxResolvedNames; i++ {
+		assert.Equal("foo", prefixed.MustRead(fmt.Sprintf("Name%d", i), v))
+	}
+	assert.Equal(maxResolvedNames, count(&st.resolved))
+
+	mt := methodTableOf(a, reflect.TypeOf(v))
+	for i := 0; i < 2*maxResolvedNames; i++ {
+		assert.Equal(-1, structMethodIndex(a, reflect.TypeOf(v), fmt.Sprintf("missing%d", i), ""))
+	}
+	assert.Equal(0, count(&mt.resolved))
+}
+
+func BenchmarkReadField(b *testing.B) {
+	a := NewAccessor()
+	u := newCacheUser()
+	p := New("address.city")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		a.readPath(p, u)
+	}
+}
+
+func BenchmarkStructField(b *testing.B) {
+	a := NewAccessor()
+	t := reflect.TypeOf(cacheUser{})
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		structField(a, t, "address")
+	}
+}
+
+// BenchmarkStructFieldUncached lists fields of the type on every lookup, as reading without cache would.
+func BenchmarkStructFieldUncached(b *testing.B) {
+	a := NewAccessor()
+	t := reflect.TypeOf(cacheUser{})
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		findStructField(a, newStructTable(a, t), "address")
+	}
+}
+
+func BenchmarkStructMethod(b *testing.B) {
+	a := NewAccessor()
+	t := reflect.TypeOf(&cacheUser{})
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		structMethodIndex(a, t, "secret", "")
+	}
+}
+
+// BenchmarkStructMethodUncached lists methods of the type on every lookup, as reading without cache would.
+func BenchmarkStructMethodUncached(b *testing.B) {
+	a := NewAccessor()
+	t := reflect.TypeOf(&cacheUser{})
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		findStructMethod(a, newMethodTable(t), "secret", "")
+	}
+}
+
+func BenchmarkReadAll(b *testing.B) {
+	a := NewAccessor()
+	u := newCacheUser()
+	p := New("*")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		a.readAllPath(p, u)
+	}
+}
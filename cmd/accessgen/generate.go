@@ -0,0 +1,511 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// typeInfo describes a type accessors are generated for.
+type typeInfo struct {
+	Name   string
+	Struct bool
+	Fields []fieldInfo
+	// Elem is element type of slice types
+	Elem string
+	// Local is true when Elem doesn't reference other packages, so that generated code can name it
+	Local bool
+}
+
+// fieldInfo describes a struct field.
+type fieldInfo struct {
+	Name string
+	Keys []string
+	Type string
+	// Elem is type pointed to by pointer fields
+	Elem  string
+	Local bool
+}
+
+// generate parses package in dir and returns formatted source of accessors for named types.
+func generate(dir string, names []string, tags []string) ([]byte, error) {
+
+	pkg, specs, err := parseDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]typeInfo, 0, len(names))
+
+	for _, name := range names {
+		spec, ok := specs[name]
+		if !ok {
+			return nil, fmt.Errorf("Type %s not found in %s", name, dir)
+		}
+
+		if spec.TypeParams != nil {
+			return nil, fmt.Errorf("Generic type %s is not supported", name)
+		}
+
+		info, err := inspect(name, spec.Type, tags)
+		if err != nil {
+			return nil, err
+		}
+
+		infos = append(infos, info)
+	}
+
+	var buf bytes.Buffer
+
+	if err := accessorsTemplate.Execute(&buf, struct {
+		Package string
+		Types   []typeInfo
+	}{pkg, infos}); err != nil {
+		return nil, err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("Generated invalid code: %s", err)
+	}
+
+	return src, nil
+}
+
+// parseDir returns package name and type declarations of non test files in dir.
+func parseDir(dir string) (string, map[string]*ast.TypeSpec, error) {
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", nil, err
+	}
+
+	var pkg string
+	specs := map[string]*ast.TypeSpec{}
+	fset := token.NewFileSet()
+
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, name, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if pkg == "" {
+			pkg = f.Name.Name
+		} else if pkg != f.Name.Name {
+			return "", nil, fmt.Errorf("Found packages %s and %s in %s", pkg, f.Name.Name, dir)
+		}
+
+		for _, decl := range f.Decls {
+			if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.TYPE {
+				for _, spec := range gd.Specs {
+					ts := spec.(*ast.TypeSpec)
+					specs[ts.Name.Name] = ts
+				}
+			}
+		}
+	}
+
+	if pkg == "" {
+		return "", nil, fmt.Errorf("No Go files found in %s", dir)
+	}
+
+	return pkg, specs, nil
+}
+
+// inspect describes type name declared as expr.
+func inspect(name string, expr ast.Expr, tags []string) (typeInfo, error) {
+
+	switch t := expr.(type) {
+	case *ast.StructType:
+		info := typeInfo{Name: name, Struct: true}
+		used := map[string]bool{}
+
+		for _, f := range t.Fields.List {
+
+			goNames := make([]string, 0, len(f.Names))
+			for _, n := range f.Names {
+				goNames = append(goNames, n.Name)
+			}
+			if len(f.Names) == 0 {
+				goNames = append(goNames, embeddedName(f.Type))
+			}
+
+			var tag reflect.StructTag
+			if f.Tag != nil {
+				value, err := strconv.Unquote(f.Tag.Value)
+				if err != nil {
+					return typeInfo{}, err
+				}
+				tag = reflect.StructTag(value)
+			}
+
+			tagName, hidden := fieldTag(tag, tags)
+			if hidden {
+				continue
+			}
+
+			for _, goName := range goNames {
+				if !ast.IsExported(goName) {
+					continue
+				}
+
+				fi := fieldInfo{Name: goName, Type: types.ExprString(f.Type), Local: isLocal(f.Type)}
+				if star, ok := f.Type.(*ast.StarExpr); ok {
+					fi.Elem = types.ExprString(star.X)
+				}
+
+				for _, key := range []string{tagName, goName, lowerCamel(goName), snakeCase(goName)} {
+					// the first field named by a key wins
+					if key != "" && !used[key] {
+						used[key] = true
+						fi.Keys = append(fi.Keys, strconv.Quote(key))
+					}
+				}
+
+				if len(fi.Keys) > 0 {
+					info.Fields = append(info.Fields, fi)
+				}
+			}
+		}
+
+		return info, nil
+
+	case *ast.ArrayType:
+		if t.Len != nil {
+			return typeInfo{}, fmt.Errorf("Array type %s is not supported, use a slice", name)
+		}
+		return typeInfo{Name: name, Elem: types.ExprString(t.Elt), Local: isLocal(t.Elt)}, nil
+	}
+
+	return typeInfo{}, fmt.Errorf("Type %s is neither a struct nor a slice", name)
+}
+
+// fieldTag returns name of field given by the first of tags naming it and whether the field is hidden.
+func fieldTag(tag reflect.StructTag, tags []string) (string, bool) {
+
+	for _, t := range tags {
+
+		value, ok := tag.Lookup(t)
+		if !ok {
+			continue
+		}
+
+		if value == "-" {
+			return "", true
+		}
+
+		if name := strings.Split(value, ",")[0]; name != "" {
+			return name, false
+		}
+	}
+
+	return "", false
+}
+
+// embeddedName returns field name of embedded type expr.
+func embeddedName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// isLocal reports whether type expr doesn't reference other packages, so that generated code can name it.
+func isLocal(expr ast.Expr) bool {
+	local := true
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if _, ok := n.(*ast.SelectorExpr); ok {
+			local = false
+		}
+		return local
+	})
+	return local
+}
+
+// words splits Go name into words, keeping initialisms like ID or HTTP together.
+func words(name string) []string {
+
+	runes := []rune(name)
+	result := []string{}
+	start := 0
+
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+
+		lowerToUpper := !unicode.IsUpper(prev) && unicode.IsUpper(cur)
+		initialismEnd := unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+		if lowerToUpper || initialismEnd || cur == '_' {
+			if i > start {
+				result = append(result, string(runes[start:i]))
+			}
+			start = i
+			if cur == '_' {
+				start++
+			}
+		}
+	}
+
+	if start < len(runes) {
+		result = append(result, string(runes[start:]))
+	}
+
+	return result
+}
+
+// lowerCamel returns name with its first word in lower case, like userID for UserID.
+func lowerCamel(name string) string {
+	w := words(name)
+	if len(w) == 0 {
+		return ""
+	}
+	return strings.ToLower(w[0]) + strings.Join(w[1:], "")
+}
+
+// snakeCase returns name in lower case with words separated by underscores, like user_id for UserID.
+func snakeCase(name string) string {
+	return strings.ToLower(strings.Join(words(name), "_"))
+}
+
+var accessorsTemplate = template.Must(template.New("accessors").Funcs(template.FuncMap{
+	"join": func(keys []string) string {
+		return strings.Join(keys, ", ")
+	},
+}).Parse(`// Code generated by accessgen; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"fmt"
+
+	"github.com/Neverbland/access"
+)
+{{range .Types}}{{if .Struct}}
+// reflect{{.Name}} exposes fields of {{.Name}} to reflection for paths its generated methods don't handle.
+// Its own methods hide them, including those promoted from embedded fields.
+type reflect{{.Name}} struct{ *{{.Name}} }
+
+func (reflect{{.Name}}) ReadPath()      {}
+func (reflect{{.Name}}) ReadPathWith()  {}
+func (reflect{{.Name}}) WritePath()     {}
+func (reflect{{.Name}}) WritePathWith() {}
+func (reflect{{.Name}}) Field()         {}
+func (reflect{{.Name}}) SetField()      {}
+
+// ReadPath implements access.PathReader.
+func (t *{{.Name}}) ReadPath(p access.Path) (interface{}, error) {
+	return t.ReadPathWith(access.DefaultAccessor(), p)
+}
+
+// ReadPathWith implements access.AccessorPathReader.
+func (t *{{.Name}}) ReadPathWith(a *access.Accessor, p access.Path) (interface{}, error) {
+	if len(p) == 0 {
+		return t, nil
+	}
+
+	if t == nil {
+		return nil, fmt.Errorf("Nil {{.Name}}: %w", access.ErrNotFound)
+	}
+
+	name, ok := p[0].(string)
+	if !ok {
+		return a.Read(p, &reflect{{.Name}}{t})
+	}
+
+	switch name {
+{{- range .Fields}}
+	case {{join .Keys}}:
+		if len(p) == 1 {
+			return t.{{.Name}}, nil
+		}
+		return a.Read(p[1:], &t.{{.Name}})
+{{- end}}
+	}
+
+	return a.Read(p, &reflect{{.Name}}{t})
+}
+
+// WritePath implements access.PathWriter.
+func (t *{{.Name}}) WritePath(p access.Path, w interface{}) error {
+	return t.WritePathWith(access.DefaultAccessor(), p, w)
+}
+
+// WritePathWith implements access.AccessorPathWriter.
+func (t *{{.Name}}) WritePathWith(a *access.Accessor, p access.Path, w interface{}) error {
+	if len(p) == 0 {
+		return fmt.Errorf("Can't write {{.Name}} itself: %w", access.ErrUnsupportedKind)
+	}
+
+	if t == nil {
+		return fmt.Errorf("Nil {{.Name}}: %w", access.ErrNotFound)
+	}
+
+	name, ok := p[0].(string)
+	if !ok {
+		return a.Write(p, &reflect{{.Name}}{t}, w)
+	}
+
+	switch name {
+{{- range .Fields}}
+	case {{join .Keys}}:
+{{- if .Local}}
+		if v, ok := w.({{.Type}}); ok && len(p) == 1 {
+			t.{{.Name}} = v
+			return nil
+		}
+{{- if .Elem}}
+		if t.{{.Name}} == nil && len(p) > 1 {
+			v := new({{.Elem}})
+			if err := a.Write(p[1:], v, w); err != nil {
+				return err
+			}
+			t.{{.Name}} = v
+			return nil
+		}
+{{- end}}
+{{- end}}
+		return a.Write(p[1:], &t.{{.Name}}, w)
+{{- end}}
+	}
+
+	return a.Write(p, &reflect{{.Name}}{t}, w)
+}
+
+// Field implements access.FieldReader.
+func (t *{{.Name}}) Field(name string) (interface{}, error) {
+	return t.ReadPath(access.Path{name})
+}
+
+// SetField implements access.FieldWriter.
+func (t *{{.Name}}) SetField(name string, v interface{}) error {
+	return t.WritePath(access.Path{name}, v)
+}
+{{else}}
+// reflect{{.Name}} is {{.Name}} without generated methods, read and written by reflection
+// at path elements they don't handle.
+type reflect{{.Name}} {{.Name}}
+
+// ReadPath implements access.PathReader.
+func (t *{{.Name}}) ReadPath(p access.Path) (interface{}, error) {
+	return t.ReadPathWith(access.DefaultAccessor(), p)
+}
+
+// ReadPathWith implements access.AccessorPathReader.
+func (t *{{.Name}}) ReadPathWith(a *access.Accessor, p access.Path) (interface{}, error) {
+	if len(p) == 0 {
+		return t, nil
+	}
+
+	if t == nil {
+		return nil, fmt.Errorf("Nil {{.Name}}: %w", access.ErrNotFound)
+	}
+
+	i, ok := p[0].(int)
+	if !ok {
+		v, err := a.Read(p, (*reflect{{.Name}})(t))
+		if r, ok := v.(reflect{{.Name}}); ok {
+			return {{.Name}}(r), err
+		}
+		return v, err
+	}
+
+	if i < 0 {
+		i += len(*t)
+	}
+
+	if i < 0 || i >= len(*t) {
+		return nil, fmt.Errorf("Index %d out of range %d: %w", p[0], len(*t), access.ErrIndexOutOfRange)
+	}
+
+	if len(p) == 1 {
+		return (*t)[i], nil
+	}
+	return a.Read(p[1:], &(*t)[i])
+}
+
+// WritePath implements access.PathWriter, appending elements written at [-] or just past the end.
+func (t *{{.Name}}) WritePath(p access.Path, w interface{}) error {
+	return t.WritePathWith(access.DefaultAccessor(), p, w)
+}
+
+// WritePathWith implements access.AccessorPathWriter.
+func (t *{{.Name}}) WritePathWith(a *access.Accessor, p access.Path, w interface{}) error {
+	if len(p) == 0 {
+		return fmt.Errorf("Can't write {{.Name}} itself: %w", access.ErrUnsupportedKind)
+	}
+
+	if t == nil {
+		return fmt.Errorf("Nil {{.Name}}: %w", access.ErrNotFound)
+	}
+
+	var i int
+
+	switch e := p[0].(type) {
+	case int:
+		i = e
+	case access.Append:
+		i = len(*t)
+	default:
+		return a.Write(p, (*reflect{{.Name}})(t), w)
+	}
+
+	if i < 0 {
+		i += len(*t)
+	}
+
+	if i < 0 || i > len(*t) {
+		return fmt.Errorf("Index %d out of range %d: %w", p[0], len(*t), access.ErrIndexOutOfRange)
+	}
+
+	if i == len(*t) {
+		*t = append(*t, make({{.Name}}, 1)...)
+		if err := a.Write(p[1:], &(*t)[i], w); err != nil {
+			*t = (*t)[:i]
+			return err
+		}
+		return nil
+	}
+{{if .Local}}
+	if v, ok := w.({{.Elem}}); ok && len(p) == 1 {
+		(*t)[i] = v
+		return nil
+	}
+{{end}}
+	return a.Write(p[1:], &(*t)[i], w)
+}
+
+// Index implements access.IndexReader.
+func (t *{{.Name}}) Index(i int) (interface{}, error) {
+	return t.ReadPath(access.Path{i})
+}
+
+// SetIndex implements access.IndexWriter.
+func (t *{{.Name}}) SetIndex(i int, v interface{}) error {
+	return t.WritePath(access.Path{i}, v)
+}
+
+// Len implements access.Lener.
+func (t *{{.Name}}) Len() int {
+	return len(*t)
+}
+{{end}}{{end}}`))
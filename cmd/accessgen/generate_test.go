@@ -0,0 +1,232 @@
+package main
+
+import (
+	"flag"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGenerate(t *testing.T) {
+	assert := assert.New(t)
+
+	src, err := generate("testdata", []string{"Order", "Customer", "Lines", "Embedded"}, []string{"access", "json"})
+	if !assert.NoError(err) {
+		return
+	}
+
+	if *update {
+		assert.NoError(os.WriteFile("testdata/order_access.go.golden", src, 0644))
+	}
+
+	golden, err := os.ReadFile("testdata/order_access.go.golden")
+	assert.NoError(err)
+	assert.Equal(string(golden), string(src))
+}
+
+// compareProgram applies the same operations to testdata types with generated accessors and
+// without them, failing when results encoded as JSON differ. PACKAGE is replaced by import path of its directory.
+const compareProgram = `package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Neverbland/access"
+
+	generated "PACKAGE/generated"
+	plain "PACKAGE/plain"
+)
+
+const doc = ` + "`" + `{
+	"id": 1,
+	"customer": {"Name": "foo", "Address": {"City": "Oslo"}},
+	"lines": [{"SKU": "a", "Qty": 1}, {"SKU": "b", "Qty": 2}, {"SKU": "c", "Qty": 3}],
+	"Meta": {"color": "red"},
+	"UserID": "u1",
+	"Source": "web"
+}` + "`" + `
+
+type operation struct {
+	op, path, from string
+	value          interface{}
+}
+
+var operations = []operation{
+	{op: "read", path: "id"},
+	{op: "read", path: "customer.name"},
+	{op: "read", path: "customer.address.city"},
+	{op: "read", path: "lines[0].sku"},
+	{op: "read", path: "lines[-1].qty"},
+	{op: "read", path: "lines[0:2]"},
+	{op: "read", path: "lines[*].sku"},
+	{op: "read", path: "meta.color"},
+	{op: "read", path: "userId"},
+	{op: "read", path: "Id"},
+	{op: "read", path: "total"},
+	{op: "read", path: "user_id"},
+	{op: "read", path: "source"},
+	{op: "read", path: "Embedded.Source"},
+	{op: "read", path: "Internal"},
+	{op: "read", path: "note"},
+	{op: "read", path: "missing"},
+	{op: "read", path: "lines[5]"},
+	{op: "write", path: "userId", value: "u2"},
+	{op: "write", path: "customer.address.city", value: "Paris"},
+	{op: "write", path: "lines[0].qty", value: 7},
+	{op: "write", path: "lines[1].qty", value: "8"},
+	{op: "write", path: "id", value: "2"},
+	{op: "write", path: "lines[0:1]", value: []interface{}{}},
+	{op: "write", path: "lines[0:2]", from: "lines[1:]"},
+	{op: "write", path: "lines[-]", from: "lines[0]"},
+	{op: "write", path: "meta.size", value: 3},
+	{op: "write", path: "customer.address", value: nil},
+	{op: "write", path: "source", value: "app"},
+	{op: "write", path: "missing", value: 1},
+	{op: "delete", path: "lines[0]"},
+	{op: "delete", path: "lines[-1]"},
+	{op: "delete", path: "meta.color"},
+	{op: "delete", path: "customer.address"},
+	{op: "delete", path: "userId"},
+	{op: "delete", path: "missing"},
+}
+
+// accessors use the tags the code was generated with, the converter must apply to nested values as well.
+var accessors = []*access.Accessor{
+	access.NewAccessor(access.WithTagNames("access", "json")),
+	access.NewAccessor(access.WithTagNames("access", "json"), access.WithConverter(access.NewConverter())),
+}
+
+// apply runs operation on doc decoded into v with a, returning results encoded as JSON.
+func apply(a *access.Accessor, o operation, v interface{}) string {
+	if err := json.Unmarshal([]byte(doc), v); err != nil {
+		panic(err)
+	}
+
+	var (
+		result interface{}
+		err    error
+	)
+
+	switch o.op {
+	case "read":
+		result, err = a.Read(o.path, v)
+	case "write":
+		w := o.value
+		if o.from != "" {
+			w = a.MustRead(o.from, v)
+		}
+		err = a.Write(o.path, v, w)
+		result = v
+	case "delete":
+		err = a.Delete(o.path, v)
+		result = v
+	}
+
+	out, jerr := json.Marshal(result)
+	if jerr != nil {
+		panic(jerr)
+	}
+
+	return fmt.Sprintf("%s error: %t", out, err != nil)
+}
+
+func main() {
+	failed := false
+
+	for i, a := range accessors {
+		for _, o := range operations {
+			g, p := apply(a, o, &generated.Order{}), apply(a, o, &plain.Order{})
+			if g != p {
+				fmt.Printf("accessor %d %s %s: generated %s, reflection %s\n", i, o.op, o.path, g, p)
+				failed = true
+			}
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+`
+
+func TestGeneratedMatchesReflection(t *testing.T) {
+	assert := assert.New(t)
+
+	if testing.Short() {
+		t.Skip("compiling generated code is slow")
+	}
+
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+
+	out, err := exec.Command(goTool, "list", "-f", "{{.ImportPath}}", ".").Output()
+	if !assert.NoError(err) {
+		return
+	}
+
+	dir, err := os.MkdirTemp("testdata", "compare")
+	if !assert.NoError(err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	types, err := os.ReadFile("testdata/order.go")
+	assert.NoError(err)
+	golden, err := os.ReadFile("testdata/order_access.go.golden")
+	assert.NoError(err)
+
+	files := map[string]string{
+		"main.go":                   strings.ReplaceAll(compareProgram, "PACKAGE", strings.TrimSpace(string(out))+"/"+filepath.ToSlash(dir)),
+		"generated/order.go":        string(types),
+		"generated/order_access.go": string(golden),
+		"plain/order.go":            string(types),
+	}
+
+	for name, src := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(os.WriteFile(path, []byte(src), 0644))
+	}
+
+	out, err = exec.Command(goTool, "run", "./"+filepath.ToSlash(dir)).CombinedOutput()
+	assert.NoError(err, string(out))
+}
+
+func TestGenerateErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := generate("testdata", []string{"Missing"}, nil)
+	assert.EqualError(err, "Type Missing not found in testdata")
+
+	_, err = generate("missing", []string{"Order"}, nil)
+	assert.EqualError(err, "No Go files found in missing")
+}
+
+func TestNames(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		name, camel, snake string
+	}{
+		{"Name", "name", "name"},
+		{"UserID", "userID", "user_id"},
+		{"HTTPServer", "httpServer", "http_server"},
+		{"CreatedAt", "createdAt", "created_at"},
+		{"Address2", "address2", "address2"},
+		{"ID", "id", "id"},
+	}
+
+	for _, c := range cases {
+		assert.Equal(c.camel, lowerCamel(c.name), c.name)
+		assert.Equal(c.snake, snakeCase(c.name), c.name)
+	}
+}
@@ -0,0 +1,75 @@
+// Command accessgen generates reflection free accessors for struct and slice types,
+// implementing access.AccessorPathReader, access.AccessorPathWriter and either access.FieldWriter
+// or access.IndexWriter with static switch statements.
+//
+// Usage:
+//
+//	accessgen -type Order,Lines [-tags json] [-output order_access.go] [dir]
+//
+// Struct fields are selected by their Go name, lower camel cased and snake cased name and by names
+// given by -tags, which should list the same tags as the Accessor reading them, none by default.
+// Unexported fields and fields tagged with `-` are skipped. Other names and path elements like
+// ranges are read and written by reflection with the Accessor reading the value, which also
+// calls getter and setter methods of the type.
+//
+// Add a go:generate directive next to the types to keep accessors up to date:
+//
+//	//go:generate accessgen -type Order
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	typeNames = flag.String("type", "", "comma separated list of type names, required")
+	tagNames  = flag.String("tags", "", "comma separated list of struct tags naming fields")
+	output    = flag.String("output", "", "output file name, <type>_access.go in the package directory by default")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: accessgen -type T[,T...] [-tags tag[,tag...]] [-output file] [dir]\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	var tags []string
+	if *tagNames != "" {
+		tags = strings.Split(*tagNames, ",")
+	}
+
+	types := strings.Split(*typeNames, ",")
+
+	src, err := generate(dir, types, tags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "accessgen: %s\n", err)
+		os.Exit(1)
+	}
+
+	name := *output
+	if name == "" {
+		name = filepath.Join(dir, strings.ToLower(types[0])+"_access.go")
+	}
+
+	if err := os.WriteFile(name, src, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "accessgen: %s\n", err)
+		os.Exit(1)
+	}
+}
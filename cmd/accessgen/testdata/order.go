@@ -0,0 +1,44 @@
+package order
+
+import "time"
+
+type Order struct {
+	ID        int       `json:"id"`
+	Customer  *Customer `json:"customer"`
+	Lines     Lines     `json:"lines"`
+	Meta      map[string]interface{}
+	CreatedAt time.Time `access:"created"`
+	Internal  string    `json:"-"`
+	UserID    string
+	note      string
+	Embedded
+}
+
+// Total is read by reflection through the getter.
+func (o *Order) Total() int {
+	total := 0
+	for _, l := range o.Lines {
+		total += l.Qty
+	}
+	return total
+}
+
+type Customer struct {
+	Name    string
+	Address *Address
+}
+
+type Address struct {
+	City string
+}
+
+type Embedded struct {
+	Source string
+}
+
+type Lines []Line
+
+type Line struct {
+	SKU string
+	Qty int
+}
@@ -0,0 +1,345 @@
+package access
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+type compiledOp int
+
+const (
+	opField compiledOp = iota
+	opMethod
+	opMapKey
+	opIndex
+)
+
+// compiledStep is a path element resolved for a concrete type.
+type compiledStep struct {
+	op     compiledOp
+	field  []int         // index of struct field
+	method int           // index of getter method of pointer to struct
+	key    reflect.Value // map key
+	index  int           // slice or array index, negative counts from the end
+}
+
+// CompiledPath is a Path resolved for values of a concrete type, so that reading and writing
+// them doesn't parse selectors nor match names. Values of other types, interfaces holding
+// values of another dynamic type and custom readers or writers fall back to the generic Path.
+type CompiledPath struct {
+	accessor *Accessor
+	path     Path
+	typ      reflect.Type
+	steps    []compiledStep
+	// rest are elements following steps, which are read by Path
+	rest Path
+	// dynamic is true when rest is read through an interface, tails are compiled per its dynamic type
+	dynamic bool
+	tails   sync.Map // reflect.Type -> *CompiledPath, nil when tail can't be compiled
+}
+
+// Compile resolves path selector s for values of type t using the default instance.
+func Compile(s interface{}, t reflect.Type) (*CompiledPath, error) {
+	return defaultAccessor().Compile(s, t)
+}
+
+// Compile resolves path selector s for values of type t, failing when the path doesn't exist in t.
+func (a *Accessor) Compile(s interface{}, t reflect.Type) (*CompiledPath, error) {
+
+	path, err := Parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.checkDepth(path); err != nil {
+		return nil, err
+	}
+
+	if t == nil {
+		return nil, Error{kindError{fmt.Errorf("Can't compile path for nil type"), ErrUnsupportedKind}, []interface{}{}}
+	}
+
+	return a.compile(path, t)
+}
+
+func (a *Accessor) compile(path Path, t reflect.Type) (*CompiledPath, error) {
+
+	c := &CompiledPath{accessor: a, path: path, typ: t}
+
+	for i, e := range path {
+
+		var ifaces []reflect.Type
+
+		switch e.(type) {
+		case string:
+			ifaces = []reflect.Type{pathReaderInterface, pathWriterInterface, fieldReaderInterface}
+		case int:
+			ifaces = []reflect.Type{pathReaderInterface, pathWriterInterface, indexReaderInterface}
+		}
+
+		// elements other than fields and indices and custom readers are left to Path
+		if ifaces == nil || customAccess(t, ifaces...) {
+			c.rest = path[i:]
+			break
+		}
+
+		if t = derefType(t); t.Kind() == reflect.Interface {
+			c.rest = path[i:]
+			c.dynamic = true
+			break
+		}
+
+		var (
+			step compiledStep
+			err  error
+		)
+
+		if s, ok := e.(string); ok {
+			step, t, err = a.compileField(t, s)
+		} else {
+			step, t, err = compileIndex(t, e.(int))
+		}
+
+		if err != nil {
+			return nil, Error{err, append([]interface{}{}, path[:i]...)}
+		}
+
+		c.steps = append(c.steps, step)
+	}
+
+	return c, nil
+}
+
+func (a *Accessor) compileField(t reflect.Type, field string) (compiledStep, reflect.Type, error) {
+
+	switch t.Kind() {
+	case reflect.Map:
+
+		if kk := t.Key().Kind(); kk != reflect.String {
+			return compiledStep{}, nil, kindError{fmt.Errorf("Map key type is not a string"), ErrUnsupportedKind}
+		}
+
+		return compiledStep{op: opMapKey, key: reflect.ValueOf(field).Convert(t.Key())}, t.Elem(), nil
+
+	case reflect.Struct:
+
+		if ft, ok := structField(a, t, field); ok {
+			return compiledStep{op: opField, field: ft.Index}, ft.Type, nil
+		}
+
+		if a.methods {
+			pt := reflect.PtrTo(t)
+
+			for _, prefix := range []string{"", "Get"} {
+
+				i := structMethodIndex(a, pt, field, prefix)
+				if i < 0 {
+					continue
+				}
+
+				if mt := pt.Method(i).Type; mt.NumIn() == 1 && mt.NumOut() == 1 {
+					return compiledStep{op: opMethod, method: i}, mt.Out(0), nil
+				}
+			}
+		}
+
+		return compiledStep{}, nil, kindError{fmt.Errorf("Struct has no field `%s` nor getter method", camelcased(field)), ErrNotFound}
+	}
+
+	return compiledStep{}, nil, kindError{fmt.Errorf("struct,map or FieldReader instance expected"), ErrUnsupportedKind}
+}
+
+func compileIndex(t reflect.Type, index int) (compiledStep, reflect.Type, error) {
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return compiledStep{op: opIndex, index: index}, t.Elem(), nil
+	}
+
+	return compiledStep{}, nil, kindError{fmt.Errorf("slice, array or IndexReader instance expected"), ErrUnsupportedKind}
+}
+
+// customAccess reports whether t, types it points to or pointers to them implement any of ifaces.
+func customAccess(t reflect.Type, ifaces ...reflect.Type) bool {
+	for {
+		for _, iface := range ifaces {
+			if implements(t, iface) || t.Kind() != reflect.Ptr && implements(reflect.PtrTo(t), iface) {
+				return true
+			}
+		}
+
+		if t.Kind() != reflect.Ptr {
+			return false
+		}
+
+		t = t.Elem()
+	}
+}
+
+// derefType returns type which pointer type t points to, through any number of pointers.
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// Path returns the compiled path.
+func (c *CompiledPath) Path() Path {
+	return c.path
+}
+
+// Read reads value at compiled path like Path.Read.
+func (c *CompiledPath) Read(v interface{}) (interface{}, error) {
+
+	if rv := reflect.ValueOf(v); rv.IsValid() && rv.Type() == c.typ {
+		if re, ok := c.read(rv); ok {
+			if !re.IsValid() {
+				return nil, nil
+			}
+			return re.Interface(), nil
+		}
+	}
+
+	// failures are reported by Path
+	return c.accessor.readPath(c.path, v)
+}
+
+// Write writes value at compiled path like Path.Write. Only paths through struct fields
+// and slice or array elements which exist are written directly.
+func (c *CompiledPath) Write(v interface{}, w interface{}) error {
+
+	if rv := reflect.ValueOf(v); rv.IsValid() && rv.Type() == c.typ && rv.Kind() == reflect.Ptr && len(c.rest) == 0 {
+		if fv, ok := c.target(rv); ok && indirectWrite(c.accessor, fv, reflect.ValueOf(w), reflect.TypeOf(w)) == nil {
+			return nil
+		}
+	}
+
+	// allocation, maps, setters and failures are handled by Path
+	return c.accessor.writePath(c.path, v, w)
+}
+
+// read reads value at compiled path from v of compiled type, ok is false when it can't be read directly.
+func (c *CompiledPath) read(v reflect.Value) (reflect.Value, bool) {
+
+	for _, s := range c.steps {
+
+		var ok bool
+		if v, ok = derefValue(v); !ok {
+			return reflect.Value{}, false
+		}
+
+		switch s.op {
+		case opField:
+			fv, err := v.FieldByIndexErr(s.field)
+			if err != nil {
+				return reflect.Value{}, false
+			}
+			v = fv
+
+		case opMethod:
+			if !v.CanAddr() {
+				return reflect.Value{}, false
+			}
+			v = v.Addr().Method(s.method).Call([]reflect.Value{})[0]
+
+		case opMapKey:
+			if v = v.MapIndex(s.key); !v.IsValid() {
+				return reflect.Value{}, false
+			}
+
+		case opIndex:
+			i := s.index
+			if i < 0 {
+				i += v.Len()
+			}
+			if i < 0 || i >= v.Len() {
+				return reflect.Value{}, false
+			}
+			v = v.Index(i)
+		}
+	}
+
+	if len(c.rest) == 0 {
+		return v, true
+	}
+
+	if c.dynamic {
+		var ok bool
+		if v, ok = derefValue(v); !ok || v.IsNil() {
+			return reflect.Value{}, false
+		}
+
+		if tail := c.tail(v.Elem().Type()); tail != nil {
+			return tail.read(v.Elem())
+		}
+	}
+
+	re, err := c.rest.read(c.accessor, v)
+	return re, err == nil
+}
+
+// target finds settable value at compiled path from pointer v, ok is false when it doesn't exist.
+func (c *CompiledPath) target(v reflect.Value) (reflect.Value, bool) {
+
+	for _, s := range c.steps {
+
+		var ok bool
+		if v, ok = derefValue(v); !ok {
+			return reflect.Value{}, false
+		}
+
+		switch s.op {
+		case opField:
+			fv, err := v.FieldByIndexErr(s.field)
+			if err != nil {
+				return reflect.Value{}, false
+			}
+			v = fv
+
+		case opIndex:
+			i := s.index
+			if i < 0 {
+				i += v.Len()
+			}
+			if i < 0 || i >= v.Len() {
+				return reflect.Value{}, false
+			}
+			v = v.Index(i)
+
+		default:
+			return reflect.Value{}, false
+		}
+	}
+
+	return v, v.CanSet()
+}
+
+// tail returns rest of the path compiled for dynamic type t.
+func (c *CompiledPath) tail(t reflect.Type) *CompiledPath {
+
+	if tail, ok := c.tails.Load(t); ok {
+		return tail.(*CompiledPath)
+	}
+
+	tail, err := c.accessor.compile(c.rest, t)
+	if err != nil {
+		tail = nil
+	}
+
+	c.tails.Store(t, tail)
+
+	return tail
+}
+
+// derefValue dereferences pointers of v, ok is false when one of them is nil.
+func derefValue(v reflect.Value) (reflect.Value, bool) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	return v, true
+}
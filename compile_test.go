@@ -0,0 +1,207 @@
+package access
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+type compileOrder struct {
+	ID       int `json:"id"`
+	Customer *compileCustomer
+	Lines    []compileLine
+	Meta     map[string]interface{}
+	Extra    interface{}
+	total    int
+}
+
+type compileCustomer struct {
+	Name string
+}
+
+type compileLine struct {
+	SKU string `json:"sku"`
+	Qty int
+}
+
+func (o *compileOrder) Total() int {
+	return o.total
+}
+
+func newCompileOrder() *compileOrder {
+	return &compileOrder{
+		ID:       1,
+		Customer: &compileCustomer{"foo"},
+		Lines:    []compileLine{{"a", 1}, {"b", 2}},
+		Meta:     map[string]interface{}{"source": "web"},
+		Extra:    &compileCustomer{"bar"},
+		total:    3,
+	}
+}
+
+func TestCompile(t *testing.T) {
+	assert := assert.New(t)
+
+	typ := reflect.TypeOf(&compileOrder{})
+	o := newCompileOrder()
+
+	cases := []struct {
+		path  string
+		value interface{}
+	}{
+		{"id", 1},
+		{"customer.name", "foo"},
+		{"lines[1].sku", "b"},
+		{"lines[-1].qty", 2},
+		{"lines[0:1]", []compileLine{{"a", 1}}},
+		{"meta.source", "web"},
+		{"extra.name", "bar"},
+		{"total", 3},
+	}
+
+	for _, c := range cases {
+		cp, err := Compile(c.path, typ)
+		assert.NoError(err, c.path)
+		val, err := cp.Read(o)
+		assert.NoError(err, c.path)
+		assert.Equal(c.value, val, c.path)
+		assert.Equal(New(c.path), cp.Path())
+	}
+
+	_, err := Compile("customer.missing", typ)
+	assert.True(errors.Is(err, ErrNotFound))
+	assert.Equal(Path{"customer"}, Path(err.(Error).Path))
+
+	_, err = Compile("lines.sku", typ)
+	assert.True(errors.Is(err, ErrUnsupportedKind))
+
+	_, err = Compile("meta[0]", typ)
+	assert.True(errors.Is(err, ErrUnsupportedKind))
+
+	_, err = Compile("[", typ)
+	assert.Error(err)
+}
+
+func TestCompiledPathFallback(t *testing.T) {
+	assert := assert.New(t)
+
+	cp, err := Compile("extra.name", reflect.TypeOf(&compileOrder{}))
+	assert.NoError(err)
+
+	o := newCompileOrder()
+
+	// dynamic type of interface changes
+	o.Extra = map[string]string{"name": "baz"}
+	assert.Equal("baz", mustCompiledRead(cp, o))
+
+	o.Extra = &Fields{map[string]interface{}{"name": "custom"}}
+	assert.Equal("custom", mustCompiledRead(cp, o))
+
+	o.Extra = nil
+	_, err = cp.Read(o)
+	assert.True(errors.Is(err, ErrNotFound))
+
+	// values of other types are read by Path
+	assert.Equal("qux", mustCompiledRead(cp, map[string]interface{}{"extra": map[string]interface{}{"name": "qux"}}))
+
+	cp, err = Compile("customer.name", reflect.TypeOf(&compileOrder{}))
+	assert.NoError(err)
+
+	o.Customer = nil
+	_, err = cp.Read(o)
+	assert.True(errors.Is(err, ErrNotFound))
+	assert.Equal(Path{"customer"}, Path(err.(Error).Path))
+
+	cp, err = Compile("lines[5].qty", reflect.TypeOf(&compileOrder{}))
+	assert.NoError(err)
+	_, err = cp.Read(o)
+	assert.True(errors.Is(err, ErrIndexOutOfRange))
+}
+
+func mustCompiledRead(cp *CompiledPath, v interface{}) interface{} {
+	val, err := cp.Read(v)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func TestCompiledPathWrite(t *testing.T) {
+	assert := assert.New(t)
+
+	typ := reflect.TypeOf(&compileOrder{})
+	o := newCompileOrder()
+
+	write := func(path string, v interface{}, w interface{}) error {
+		cp, err := Compile(path, typ)
+		assert.NoError(err, path)
+		return cp.Write(v, w)
+	}
+
+	assert.NoError(write("id", o, 2))
+	assert.Equal(2, o.ID)
+
+	assert.NoError(write("lines[-1].qty", o, 5))
+	assert.Equal(5, o.Lines[1].Qty)
+
+	// allocation and growth are handled by Path
+	o.Customer = nil
+	assert.NoError(write("customer.name", o, "new"))
+	assert.Equal("new", o.Customer.Name)
+
+	assert.NoError(write("lines[2].sku", o, "c"))
+	assert.Equal("c", o.Lines[2].SKU)
+
+	assert.NoError(write("meta.source", o, "api"))
+	assert.Equal("api", o.Meta["source"])
+
+	assert.NoError(write("extra.name", o, "baz"))
+	assert.Equal("baz", o.Extra.(*compileCustomer).Name)
+
+	err := write("id", o, "foo")
+	assert.True(errors.Is(err, ErrTypeMismatch))
+	assert.Equal(2, o.ID)
+
+	assert.Error(write("id", *o, 3))
+}
+
+func BenchmarkCompiledRead(b *testing.B) {
+	o := newCompileOrder()
+	cp, _ := Compile("lines[1].sku", reflect.TypeOf(o))
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		cp.Read(o)
+	}
+}
+
+func BenchmarkPathRead(b *testing.B) {
+	o := newCompileOrder()
+	p := New("lines[1].sku")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p.Read(o)
+	}
+}
+
+func BenchmarkCompiledWrite(b *testing.B) {
+	o := newCompileOrder()
+	cp, _ := Compile("customer.name", reflect.TypeOf(o))
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		cp.Write(o, "foo")
+	}
+}
+
+func BenchmarkPathWrite(b *testing.B) {
+	o := newCompileOrder()
+	p := New("customer.name")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p.Write(o, "foo")
+	}
+}
@@ -0,0 +1,289 @@
+package access
+
+import (
+	"encoding"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	textMarshalerInterface   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerInterface = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType             = reflect.TypeOf(time.Duration(0))
+)
+
+// ConvertFunc converts value to the type it was registered for.
+type ConvertFunc func(interface{}) (interface{}, error)
+
+type convertKey struct {
+	from, to reflect.Type
+}
+
+// Converter converts values between types. Besides registered functions it converts
+// strings to and from numbers and bools, parses time.Duration and encoding.TextUnmarshaler
+// values from strings, formats encoding.TextMarshaler values, and widens or narrows numbers
+// failing when the value overflows the target type or loses its fraction.
+type Converter struct {
+	mu    sync.RWMutex
+	funcs map[convertKey]ConvertFunc
+}
+
+func NewConverter() *Converter {
+	return &Converter{funcs: map[convertKey]ConvertFunc{}}
+}
+
+// Register sets function converting values of type from into type to, taking precedence over built-in conversions.
+func (c *Converter) Register(from, to reflect.Type, fn ConvertFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.funcs == nil {
+		c.funcs = map[convertKey]ConvertFunc{}
+	}
+
+	c.funcs[convertKey{from, to}] = fn
+}
+
+// Convert converts v into type t, failing with error matching ErrTypeMismatch.
+func (c *Converter) Convert(v interface{}, t reflect.Type) (interface{}, error) {
+
+	if v == nil {
+		return nil, kindError{fmt.Errorf("Can't convert nil to %s", t), ErrTypeMismatch}
+	}
+
+	cv, err := c.convert(reflect.ValueOf(v), t)
+	if err != nil {
+		return nil, err
+	}
+
+	return cv.Interface(), nil
+}
+
+func (c *Converter) convert(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+
+	vt := v.Type()
+
+	if vt.AssignableTo(t) {
+		return v, nil
+	}
+
+	c.mu.RLock()
+	fn, ok := c.funcs[convertKey{vt, t}]
+	c.mu.RUnlock()
+
+	if ok {
+		val, err := fn(v.Interface())
+		if err != nil {
+			return reflect.Value{}, kindError{err, ErrTypeMismatch}
+		}
+
+		rv := reflect.ValueOf(val)
+		if !rv.IsValid() || !rv.Type().AssignableTo(t) {
+			return reflect.Value{}, kindError{fmt.Errorf("Converter from %s returned %T instead of %s", vt, val, t), ErrTypeMismatch}
+		}
+
+		return rv, nil
+	}
+
+	if vt.Kind() == reflect.String {
+		s := v.String()
+
+		if reflect.PtrTo(t).Implements(textUnmarshalerInterface) {
+			rv := reflect.New(t)
+			if err := rv.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+				return reflect.Value{}, kindError{err, ErrTypeMismatch}
+			}
+			return rv.Elem(), nil
+		}
+
+		if t == durationType {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return reflect.Value{}, kindError{err, ErrTypeMismatch}
+			}
+			return reflect.ValueOf(d), nil
+		}
+
+		return parseValue(s, t)
+	}
+
+	if t.Kind() == reflect.String {
+
+		if vt.Implements(textMarshalerInterface) {
+			text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+			if err != nil {
+				return reflect.Value{}, kindError{err, ErrTypeMismatch}
+			}
+			return reflect.ValueOf(string(text)).Convert(t), nil
+		}
+
+		if vt == durationType {
+			return reflect.ValueOf(v.Interface().(time.Duration).String()).Convert(t), nil
+		}
+
+		return formatValue(v, t)
+	}
+
+	if isNumber(vt.Kind()) && isNumber(t.Kind()) {
+		return convertNumber(v, t)
+	}
+
+	if vt.Kind() == reflect.Bool && t.Kind() == reflect.Bool {
+		return v.Convert(t), nil
+	}
+
+	return reflect.Value{}, kindError{fmt.Errorf("Can't convert %s to %s", vt, t), ErrTypeMismatch}
+}
+
+// parseValue parses string s into number, bool or string type t.
+func parseValue(s string, t reflect.Type) (reflect.Value, error) {
+
+	rv := reflect.New(t).Elem()
+
+	var err error
+
+	switch k := t.Kind(); {
+	case isInt(k):
+		var i int64
+		if i, err = strconv.ParseInt(s, 10, t.Bits()); err == nil {
+			rv.SetInt(i)
+		}
+	case isUint(k):
+		var u uint64
+		if u, err = strconv.ParseUint(s, 10, t.Bits()); err == nil {
+			rv.SetUint(u)
+		}
+	case isFloat(k):
+		var f float64
+		if f, err = strconv.ParseFloat(s, t.Bits()); err == nil {
+			rv.SetFloat(f)
+		}
+	case k == reflect.String:
+		rv.SetString(s)
+	case k == reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(s); err == nil {
+			rv.SetBool(b)
+		}
+	default:
+		err = fmt.Errorf("Can't convert string to %s", t)
+	}
+
+	if err != nil {
+		return reflect.Value{}, kindError{err, ErrTypeMismatch}
+	}
+
+	return rv, nil
+}
+
+// formatValue formats number or bool v as string type t.
+func formatValue(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+
+	var s string
+
+	switch k := v.Kind(); {
+	case isInt(k):
+		s = strconv.FormatInt(v.Int(), 10)
+	case isUint(k):
+		s = strconv.FormatUint(v.Uint(), 10)
+	case isFloat(k):
+		s = strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())
+	case k == reflect.Bool:
+		s = strconv.FormatBool(v.Bool())
+	default:
+		return reflect.Value{}, kindError{fmt.Errorf("Can't convert %s to %s", v.Type(), t), ErrTypeMismatch}
+	}
+
+	return reflect.ValueOf(s).Convert(t), nil
+}
+
+// convertNumber converts between numeric types failing on overflow and lost fraction.
+func convertNumber(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+
+	rv := reflect.New(t).Elem()
+	k := t.Kind()
+
+	overflow := func() (reflect.Value, error) {
+		return reflect.Value{}, kindError{fmt.Errorf("Value %v overflows %s", v, t), ErrTypeMismatch}
+	}
+
+	switch vk := v.Kind(); {
+	case isInt(vk):
+		i := v.Int()
+		switch {
+		case isInt(k):
+			if rv.OverflowInt(i) {
+				return overflow()
+			}
+			rv.SetInt(i)
+		case isUint(k):
+			if i < 0 || rv.OverflowUint(uint64(i)) {
+				return overflow()
+			}
+			rv.SetUint(uint64(i))
+		default:
+			rv.SetFloat(float64(i))
+		}
+
+	case isUint(vk):
+		u := v.Uint()
+		switch {
+		case isInt(k):
+			if u > math.MaxInt64 || rv.OverflowInt(int64(u)) {
+				return overflow()
+			}
+			rv.SetInt(int64(u))
+		case isUint(k):
+			if rv.OverflowUint(u) {
+				return overflow()
+			}
+			rv.SetUint(u)
+		default:
+			rv.SetFloat(float64(u))
+		}
+
+	default:
+		f := v.Float()
+		switch {
+		case isFloat(k):
+			if rv.OverflowFloat(f) {
+				return overflow()
+			}
+			rv.SetFloat(f)
+		case f != math.Trunc(f) || math.IsInf(f, 0):
+			return reflect.Value{}, kindError{fmt.Errorf("Value %v is not an integer", v), ErrTypeMismatch}
+		case isInt(k):
+			if f < math.MinInt64 || f >= math.MaxInt64 || rv.OverflowInt(int64(f)) {
+				return overflow()
+			}
+			rv.SetInt(int64(f))
+		default:
+			if f < 0 || f >= math.MaxUint64 || rv.OverflowUint(uint64(f)) {
+				return overflow()
+			}
+			rv.SetUint(uint64(f))
+		}
+	}
+
+	return rv, nil
+}
+
+func isInt(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUint(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+func isFloat(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+func isNumber(k reflect.Kind) bool {
+	return isInt(k) || isUint(k) || isFloat(k)
+}
@@ -0,0 +1,200 @@
+package access
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type Level int
+
+type Name string
+
+func TestConvert(t *testing.T) {
+	assert := assert.New(t)
+
+	c := NewConverter()
+
+	cases := []struct {
+		value    interface{}
+		expected interface{}
+	}{
+		{"42", 42},
+		{"-42", int8(-42)},
+		{"42", uint16(42)},
+		{"1.5", float32(1.5)},
+		{"true", true},
+		{"foo", Name("foo")},
+		{"7", Level(7)},
+		{42, "42"},
+		{uint(42), "42"},
+		{1.5, "1.5"},
+		{float32(0.1), "0.1"},
+		{true, "true"},
+		{Level(3), "3"},
+		{42, int8(42)},
+		{-1, int64(-1)},
+		{uint8(200), 200},
+		{42.0, 42},
+		{42.0, uint8(42)},
+		{42, 42.0},
+		{uint64(42), float32(42)},
+		{1e300, 1e300},
+		{Level(1), int32(1)},
+		{"1m30s", 90 * time.Second},
+		{90 * time.Second, "1m30s"},
+		{"2020-01-02T03:04:05Z", time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), "2020-01-02T03:04:05Z"},
+		{"127.0.0.1", net.IPv4(127, 0, 0, 1)},
+	}
+
+	for _, c2 := range cases {
+		val, err := c.Convert(c2.value, reflect.TypeOf(c2.expected))
+		assert.NoError(err, "%#v", c2.value)
+		assert.Equal(c2.expected, val, "%#v", c2.value)
+	}
+
+	invalid := []struct {
+		value interface{}
+		typ   interface{}
+	}{
+		{"foo", 0},
+		{"300", int8(0)},
+		{"-1", uint(0)},
+		{"maybe", false},
+		{"1x", time.Duration(0)},
+		{"yesterday", time.Time{}},
+		{300, int8(0)},
+		{-1, uint(0)},
+		{uint64(math.MaxUint64), int64(0)},
+		{1.5, 0},
+		{math.NaN(), 0},
+		{math.Inf(1), 0},
+		{1e20, int64(0)},
+		{-1.0, uint(0)},
+		{1e300, float32(0)},
+		{[]int{}, ""},
+		{true, 0},
+		{nil, 0},
+	}
+
+	for _, i := range invalid {
+		_, err := c.Convert(i.value, reflect.TypeOf(i.typ))
+		assert.True(errors.Is(err, ErrTypeMismatch), "%#v", i.value)
+	}
+
+	c.Register(reflect.TypeOf(""), reflect.TypeOf(0), func(v interface{}) (interface{}, error) {
+		return len(v.(string)), nil
+	})
+	c.Register(reflect.TypeOf(""), reflect.TypeOf(false), func(v interface{}) (interface{}, error) {
+		return nil, fmt.Errorf("No bools")
+	})
+	c.Register(reflect.TypeOf(""), reflect.TypeOf(Level(0)), func(v interface{}) (interface{}, error) {
+		return "wrong", nil
+	})
+
+	val, err := c.Convert("foo", reflect.TypeOf(0))
+	assert.NoError(err)
+	assert.Equal(3, val)
+
+	_, err = c.Convert("true", reflect.TypeOf(false))
+	assert.True(errors.Is(err, ErrTypeMismatch))
+
+	_, err = c.Convert("1", reflect.TypeOf(Level(0)))
+	assert.True(errors.Is(err, ErrTypeMismatch))
+
+	var zero Converter
+	val, err = zero.Convert("1", reflect.TypeOf(0))
+	assert.NoError(err)
+	assert.Equal(1, val)
+}
+
+type convertedUser struct {
+	Age     int32
+	Score   *float64
+	Timeout time.Duration
+	Created time.Time
+	Tags    []string
+	Attrs   map[string]int
+	level   Level
+}
+
+func (u *convertedUser) Level() Level {
+	return u.level
+}
+
+func (u *convertedUser) SetLevel(l Level) {
+	u.level = l
+}
+
+func TestWriteConverted(t *testing.T) {
+	assert := assert.New(t)
+
+	user := &convertedUser{Tags: []string{"a"}}
+
+	assert.Error(Write("age", user, "42"))
+
+	SetConverter(NewConverter())
+	defer SetConverter(nil)
+
+	assert.NoError(Write("age", user, "42"))
+	assert.NoError(Write("score", user, 1))
+	assert.NoError(Write("timeout", user, "5s"))
+	assert.NoError(Write("created", user, "2020-01-02T03:04:05Z"))
+	assert.NoError(Write("tags[0]", user, 1))
+	assert.NoError(Write("tags[-]", user, true))
+	assert.NoError(Write("attrs.x", user, 1.0))
+	assert.NoError(Write("level", user, "3"))
+
+	score := 1.0
+	assert.Equal(&convertedUser{
+		Age:     42,
+		Score:   &score,
+		Timeout: 5 * time.Second,
+		Created: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Tags:    []string{"1", "true"},
+		Attrs:   map[string]int{"x": 1},
+		level:   3,
+	}, user)
+
+	err := Write("age", user, 1e10)
+	assert.True(errors.Is(err, ErrTypeMismatch))
+	assert.True(strings.HasPrefix(err.Error(), "Value 1e+10 overflows int32"), err.Error())
+	assert.True(errors.Is(Write("attrs.x", user, 1.5), ErrTypeMismatch))
+	assert.True(errors.Is(Write("level", user, "high"), ErrTypeMismatch))
+	assert.Equal(int32(42), user.Age)
+}
+
+func TestGetConverted(t *testing.T) {
+	assert := assert.New(t)
+
+	data := map[string]interface{}{"age": 42.0, "name": "foo", "timeout": "1s"}
+
+	_, err := Get[int](data, "age")
+	assert.True(errors.Is(err, ErrTypeMismatch))
+	assert.Equal(1, GetOr(data, "age", 1))
+
+	SetConverter(NewConverter())
+	defer SetConverter(nil)
+
+	age, err := Get[int](data, "age")
+	assert.NoError(err)
+	assert.Equal(42, age)
+
+	assert.Equal("42", MustGet[string](data, "age"))
+	assert.Equal(time.Second, MustGet[time.Duration](data, "timeout"))
+	assert.Equal(int8(42), GetOr(data, "age", int8(1)))
+	assert.Equal(1, GetOr(data, "name", 1))
+
+	_, err = Get[int](data, "name")
+	assert.True(errors.Is(err, ErrTypeMismatch))
+	if assert.IsType(Error{}, err) {
+		assert.Equal([]interface{}{"name"}, err.(Error).Path)
+	}
+}
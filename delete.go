@@ -0,0 +1,203 @@
+package access
+
+import (
+	"fmt"
+	"reflect"
+)
+
+var (
+	fieldDeleterInterface = reflect.TypeOf((*FieldDeleter)(nil)).Elem()
+	indexDeleterInterface = reflect.TypeOf((*IndexDeleter)(nil)).Elem()
+)
+
+// FieldDeleter is implemented by FieldWriter instances which support deleting fields.
+type FieldDeleter interface {
+	FieldWriter
+	DeleteField(string) error
+}
+
+// IndexDeleter is implemented by IndexWriter instances which support deleting elements.
+// Negative indices are resolved before calling DeleteIndex when Lener is implemented.
+type IndexDeleter interface {
+	IndexWriter
+	DeleteIndex(int) error
+}
+
+func Delete(s interface{}, v interface{}) error {
+	return defaultAccessor().Delete(s, v)
+}
+
+// Delete removes value at path: map keys are deleted, slice elements are removed shifting
+// the following ones, array elements are shifted with the last one zeroed and struct fields are zeroed.
+// Every existing location matched by wildcard path is deleted, reporting MultiError keyed by concrete path.
+func (path Path) Delete(v interface{}) error {
+	return defaultAccessor().deletePath(path, v)
+}
+
+func (a *Accessor) deletePath(path Path, v interface{}) error {
+
+	if err := a.checkDepth(path); err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() != reflect.Ptr {
+		return Error{kindError{fmt.Errorf("Non pointer value"), ErrNotAddressable}, []interface{}{}}
+	}
+
+	if path.multiIndex() < 0 {
+		return path.delete(a, v)
+	}
+
+	paths, err := path.expand(a, rv)
+	if err != nil {
+		return err
+	}
+
+	errs := MultiError{}
+
+	// later elements are deleted first, so that removing them doesn't shift preceding ones
+	for i := len(paths) - 1; i >= 0; i-- {
+		// locations missing below a wildcard are skipped
+		if _, err := paths[i].read(a, rv); err != nil {
+			continue
+		}
+
+		if err := paths[i].delete(a, v); err != nil {
+			errs[paths[i].String()] = err
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func (path Path) delete(a *Accessor, v interface{}) error {
+
+	if len(path) == 0 {
+		return Error{fmt.Errorf("Can't delete root value"), []interface{}{}}
+	}
+
+	parentPath := path[: len(path)-1 : len(path)-1]
+
+	parent, err := parentPath.read(a, reflect.ValueOf(v))
+	if err != nil {
+		return err
+	}
+
+	if !parent.IsValid() {
+		return Error{kindError{fmt.Errorf("struct, map, slice or deleter instance expected"), ErrNotFound}, append([]interface{}{}, parentPath...)}
+	}
+
+	switch s := path[len(path)-1].(type) {
+	case string:
+		err = deleteField(a, v, parentPath, parent, s)
+	case int:
+		err = deleteIndex(a, v, parentPath, parent, s)
+	default:
+		err = kindError{fmt.Errorf("Path element `%s` can't be deleted", Path{s}), ErrUnsupportedKind}
+	}
+
+	if err != nil {
+		if _, ok := err.(Error); !ok {
+			err = Error{err, append([]interface{}{}, parentPath...)}
+		}
+	}
+
+	return err
+}
+
+func deleteField(a *Accessor, v interface{}, parentPath Path, parent reflect.Value, field string) error {
+
+	if d, ok := indirectRead(parent, fieldDeleterInterface).Interface().(FieldDeleter); ok {
+		return d.DeleteField(field)
+	}
+
+	parent = indirectValue(parent)
+
+	switch parent.Kind() {
+	case reflect.Map:
+
+		if kk := parent.Type().Key().Kind(); kk != reflect.String {
+			return kindError{fmt.Errorf("Map key type is not a string"), ErrUnsupportedKind}
+		}
+
+		key := reflect.ValueOf(field).Convert(parent.Type().Key())
+
+		if !parent.MapIndex(key).IsValid() {
+			return kindError{fmt.Errorf("Map key not exists"), ErrNotFound}
+		}
+
+		parent.SetMapIndex(key, reflect.Value{})
+		return nil
+
+	case reflect.Struct:
+
+		path := append(parentPath, field)
+
+		fv, err := path.read(a, reflect.ValueOf(v))
+		if err != nil {
+			return err
+		}
+
+		if !fv.IsValid() || fv.Kind() == reflect.Interface {
+			return a.writePath(path, v, nil)
+		}
+
+		return a.writePath(path, v, reflect.Zero(fv.Type()).Interface())
+	}
+
+	return kindError{fmt.Errorf("struct, map or FieldDeleter instance expected"), ErrUnsupportedKind}
+}
+
+func deleteIndex(a *Accessor, v interface{}, parentPath Path, parent reflect.Value, index int) error {
+
+	if d, ok := indirectRead(parent, indexDeleterInterface).Interface().(IndexDeleter); ok {
+		index, err := resolveReaderIndex(d, index)
+		if err != nil {
+			return err
+		}
+		return d.DeleteIndex(index)
+	}
+
+	parent = indirectValue(parent)
+
+	switch parent.Kind() {
+	case reflect.Slice, reflect.Array:
+
+		index, err := resolveIndex(index, parent.Len())
+		if err != nil {
+			return err
+		}
+
+		if index >= parent.Len() {
+			return kindError{fmt.Errorf("Index %d out of range %d.", index, parent.Len()), ErrIndexOutOfRange}
+		}
+
+		if parent.Kind() == reflect.Slice {
+			end := index + 1
+			return a.writePath(append(parentPath, Range{Start: &index, End: &end}), v, reflect.MakeSlice(parent.Type(), 0, 0).Interface())
+		}
+
+		array := reflect.New(parent.Type()).Elem()
+		array.Set(parent)
+		reflect.Copy(array.Slice(index, array.Len()), array.Slice(index+1, array.Len()))
+		array.Index(array.Len() - 1).Set(reflect.Zero(parent.Type().Elem()))
+
+		return a.writePath(parentPath, v, array.Interface())
+	}
+
+	return kindError{fmt.Errorf("slice, array or IndexDeleter instance expected"), ErrUnsupportedKind}
+}
+
+// indirectValue dereferences pointers and interfaces of v.
+func indirectValue(v reflect.Value) reflect.Value {
+	for (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
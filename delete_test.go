@@ -0,0 +1,131 @@
+package access
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type DeleteIndexes struct {
+	values []interface{}
+}
+
+func (d *DeleteIndexes) Index(i int) (interface{}, error) {
+	return d.values[i], nil
+}
+
+func (d *DeleteIndexes) SetIndex(i int, v interface{}) error {
+	d.values[i] = v
+	return nil
+}
+
+func (d *DeleteIndexes) DeleteIndex(i int) error {
+	d.values = append(d.values[:i], d.values[i+1:]...)
+	return nil
+}
+
+func (d *DeleteIndexes) Len() int {
+	return len(d.values)
+}
+
+type DeleteFields map[string]interface{}
+
+func (d DeleteFields) Field(f string) (interface{}, error) {
+	return d[f], nil
+}
+
+func (d DeleteFields) SetField(f string, v interface{}) error {
+	d[f] = v
+	return nil
+}
+
+func (d DeleteFields) DeleteField(f string) error {
+	delete(d, "_"+f)
+	return nil
+}
+
+type deleteUser struct {
+	Name    string
+	Age     int
+	Tags    []string
+	Scores  [3]int
+	Friend  *deleteUser
+	Extra   interface{}
+	Indexes *DeleteIndexes
+	Fields  DeleteFields
+}
+
+func TestDelete(t *testing.T) {
+	assert := assert.New(t)
+
+	user := &deleteUser{
+		Name:    "foo",
+		Age:     20,
+		Tags:    []string{"a", "b", "c", "d"},
+		Scores:  [3]int{1, 2, 3},
+		Friend:  &deleteUser{Name: "bar"},
+		Extra:   map[string]interface{}{"x": 1, "y": []interface{}{1, 2}},
+		Indexes: &DeleteIndexes{[]interface{}{1, 2, 3}},
+		Fields:  DeleteFields{"_a": 1, "_b": 2},
+	}
+
+	assert.NoError(Delete("name", user))
+	assert.NoError(Delete("age", user))
+	assert.NoError(Delete("tags[1]", user))
+	assert.NoError(Delete("tags[-1]", user))
+	assert.NoError(Delete("scores[0]", user))
+	assert.NoError(Delete("friend.name", user))
+	assert.NoError(Delete("extra.x", user))
+	assert.NoError(Delete("extra.y[0]", user))
+	assert.NoError(Delete("indexes[-3]", user))
+	assert.NoError(Delete("fields.a", user))
+
+	assert.Equal(&deleteUser{
+		Tags:    []string{"a", "c"},
+		Scores:  [3]int{2, 3, 0},
+		Friend:  &deleteUser{},
+		Extra:   map[string]interface{}{"y": []interface{}{2}},
+		Indexes: &DeleteIndexes{[]interface{}{2, 3}},
+		Fields:  DeleteFields{"_b": 2},
+	}, user)
+
+	assert.NoError(Delete("friend", user))
+	assert.Nil(user.Friend)
+
+	err := Delete("extra.x", user)
+	if assert.IsType(Error{}, err) {
+		assert.Equal([]interface{}{"extra"}, err.(Error).Path)
+	}
+
+	assert.Error(Delete("tags[2]", user))
+	assert.Error(Delete("tags[-3]", user))
+	assert.Error(Delete("unknown", user))
+	assert.Error(Delete("friend.name", user))
+	assert.Error(Delete("tags[-]", user))
+	assert.Error(Delete("tags", *user))
+	assert.Error(Path{}.Delete(user))
+}
+
+func TestDeleteWildcard(t *testing.T) {
+	assert := assert.New(t)
+
+	data := map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"id": 1, "tmp": true},
+			map[string]interface{}{"id": 2, "tmp": false},
+			map[string]interface{}{"id": 3},
+		},
+		"list": []int{1, 2, 3},
+	}
+
+	assert.NoError(Delete("items[*].tmp", &data))
+	assert.NoError(Delete("list[*]", &data))
+
+	assert.Equal(map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"id": 1},
+			map[string]interface{}{"id": 2},
+			map[string]interface{}{"id": 3},
+		},
+		"list": []int{},
+	}, data)
+}
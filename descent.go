@@ -0,0 +1,91 @@
+package access
+
+import (
+	"reflect"
+)
+
+// Descent is a path element applying the following element at every depth, written as `..`.
+type Descent struct{}
+
+// visitKey identifies a pointer, map or slice being visited while descending.
+type visitKey struct {
+	typ reflect.Type
+	ptr uintptr
+	len int
+}
+
+// descendants lists v and all values nested in it, together with their paths relative to v.
+// When tail starts with a concrete element, only values where it exists are listed.
+// Values referencing one of their parents are skipped to break cycles, values deeper than max depth are skipped.
+func descendants(a *Accessor, v reflect.Value, tail Path) ([]Path, []reflect.Value) {
+	var (
+		paths  []Path
+		values []reflect.Value
+	)
+
+	visiting := map[visitKey]bool{}
+
+	var walk func(v reflect.Value, path Path)
+
+	walk = func(v reflect.Value, path Path) {
+
+		enter := func(k visitKey) bool {
+			if visiting[k] {
+				return false
+			}
+			visiting[k] = true
+			return true
+		}
+
+		for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && !v.IsNil() {
+			if v.Kind() == reflect.Ptr {
+				k := visitKey{v.Type(), v.Pointer(), 0}
+				if !enter(k) {
+					return
+				}
+				defer delete(visiting, k)
+			}
+			v = v.Elem()
+		}
+
+		if !v.IsValid() {
+			return
+		}
+
+		if (v.Kind() == reflect.Map || v.Kind() == reflect.Slice) && !v.IsNil() {
+			k := visitKey{v.Type(), v.Pointer(), v.Len()}
+			if !enter(k) {
+				return
+			}
+			defer delete(visiting, k)
+		}
+
+		if len(tail) == 0 || tail.multiIndex() == 0 {
+			paths = append(paths, path)
+			values = append(values, v)
+		} else if _, err := tail[:1].read(a, v); err == nil {
+			paths = append(paths, path)
+			values = append(values, v)
+		}
+
+		if a.maxDepth > 0 && len(path) >= a.maxDepth {
+			return
+		}
+
+		keys, vals, err := elements(a, v)
+		if err != nil {
+			return
+		}
+
+		for i, key := range keys {
+			cp := make(Path, 0, len(path)+1)
+			cp = append(cp, path...)
+			cp = append(cp, key)
+			walk(vals[i], cp)
+		}
+	}
+
+	walk(v, Path{})
+
+	return paths, values
+}
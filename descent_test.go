@@ -0,0 +1,136 @@
+package access
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type descentNode struct {
+	Id       int
+	Children []*descentNode
+	Parent   *descentNode
+}
+
+func TestDescentParse(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(Path{Descent{}, "id"}, New("..id"))
+	assert.Equal(Path{"a", Descent{}, "id"}, New("a..id"))
+	assert.Equal(Path{"a", Descent{}, 0}, New("a..[0]"))
+	assert.Equal(Path{Descent{}, Wildcard{}}, New("..*"))
+	assert.Equal(Path{"a", Descent{}, "b", "c"}, New("a..b.c"))
+
+	assert.Equal("..id", New("..id").String())
+	assert.Equal("a..b.c", New("a..b.c").String())
+	assert.Equal("a..[0]", New("a..[0]").String())
+
+	assert.Panics(func() { New("a..") })
+	assert.Panics(func() { New("a...b") })
+	assert.Panics(func() { New("...b") })
+}
+
+func TestDescentRead(t *testing.T) {
+	assert := assert.New(t)
+
+	doc := map[string]interface{}{
+		"id":    1,
+		"items": []interface{}{map[string]interface{}{"id": 2}, &descentNode{Id: 3}},
+	}
+
+	_, err := Read("..id", doc)
+	assert.True(errors.Is(err, ErrUnsupportedKind))
+
+	ids, err := ReadAll("..id", doc)
+	assert.NoError(err)
+	assert.Equal([]Match{
+		{Path{"id"}, 1},
+		{Path{"items", 0, "id"}, 2},
+		{Path{"items", 1, "id"}, 3},
+	}, ids)
+
+	ids, err = ReadAll("items..id", doc)
+	assert.NoError(err)
+	assert.Equal([]Match{
+		{Path{"items", 0, "id"}, 2},
+		{Path{"items", 1, "id"}, 3},
+	}, ids)
+
+	ids, err = ReadAll("..missing", doc)
+	assert.NoError(err)
+	assert.Equal([]Match{}, ids)
+
+	_, err = ReadAll("missing..id", doc)
+	assert.Error(err)
+}
+
+func TestDescentReadAll(t *testing.T) {
+	assert := assert.New(t)
+
+	doc := map[string]interface{}{
+		"id": 1,
+		"items": []interface{}{
+			map[string]interface{}{"id": 2, "name": "foo"},
+			map[string]interface{}{"name": "bar"},
+			&descentNode{Id: 3},
+		},
+		"meta": Fields{map[string]interface{}{"id": 4}},
+	}
+
+	matches, err := ReadAll("..id", doc)
+	assert.NoError(err)
+	assert.Equal([]Match{
+		{Path{"id"}, 1},
+		{Path{"items", 0, "id"}, 2},
+		{Path{"items", 2, "id"}, 3},
+		{Path{"meta", "id"}, 4},
+	}, matches)
+
+	matches, err = ReadAll("items..name", doc)
+	assert.NoError(err)
+	assert.Equal([]Match{
+		{Path{"items", 0, "name"}, "foo"},
+		{Path{"items", 1, "name"}, "bar"},
+	}, matches)
+
+	//FieldReader is matched where it is reachable by concrete element
+	matches, err = ReadAll("meta..id", doc)
+	assert.NoError(err)
+	assert.Equal([]Match{{Path{"meta", "id"}, 4}}, matches)
+
+	//pointer cycles are visited only once
+	root := &descentNode{Id: 1}
+	child := &descentNode{Id: 2, Parent: root}
+	root.Children = []*descentNode{child}
+	child.Children = []*descentNode{root}
+
+	matches, err = ReadAll("..id", root)
+	assert.NoError(err)
+	assert.Equal([]Match{
+		{Path{"id"}, 1},
+		{Path{"Children", 0, "id"}, 2},
+	}, matches)
+
+	self := []interface{}{1}
+	self[0] = self
+	matches, err = ReadAll("..[0]", self)
+	assert.NoError(err)
+	assert.Len(matches, 1)
+}
+
+func TestDescentWrite(t *testing.T) {
+	assert := assert.New(t)
+
+	doc := map[string]interface{}{
+		"id":    1,
+		"items": []interface{}{map[string]interface{}{"id": 2}, map[string]interface{}{"name": "bar"}},
+	}
+
+	assert.NoError(Write("..id", &doc, 0))
+	assert.Equal(0, MustRead("id", doc))
+	assert.Equal(0, MustRead("items[0].id", doc))
+
+	//missing locations are not created
+	_, err := Read("items[1].id", doc)
+	assert.Error(err)
+}
@@ -0,0 +1,25 @@
+package access
+
+import (
+	"errors"
+)
+
+// Kinds of failures, which errors returned by this package can be matched against with errors.Is.
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrIndexOutOfRange = errors.New("index out of range")
+	ErrTypeMismatch    = errors.New("type mismatch")
+	ErrNotAddressable  = errors.New("not addressable")
+	ErrUnsupportedKind = errors.New("unsupported kind")
+	ErrMaxDepth        = errors.New("max depth exceeded")
+)
+
+// kindError is an error of one of the kinds above keeping its own message, errors.Is and errors.As match both.
+type kindError struct {
+	error
+	kind error
+}
+
+func (e kindError) Unwrap() []error {
+	return []error{e.error, e.kind}
+}
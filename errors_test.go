@@ -0,0 +1,69 @@
+package access
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+type errorsUser struct {
+	Name  string
+	Tags  []string
+	Attrs map[string]interface{}
+	Ints  map[int]string
+}
+
+func TestErrorKinds(t *testing.T) {
+	assert := assert.New(t)
+
+	user := &errorsUser{Tags: []string{"a"}, Attrs: map[string]interface{}{"x": 1}}
+
+	cases := []struct {
+		err  error
+		kind error
+		path []interface{}
+	}{
+		{Delete("attrs.y", user), ErrNotFound, []interface{}{"attrs"}},
+		{func() error { _, err := Read("attrs.y", user); return err }(), ErrNotFound, []interface{}{"attrs"}},
+		{func() error { _, err := Read("unknown", user); return err }(), ErrNotFound, []interface{}{}},
+		{func() error { _, err := Read("tags[1]", user); return err }(), ErrIndexOutOfRange, []interface{}{"tags"}},
+		{func() error { _, err := Read("tags[-2]", user); return err }(), ErrIndexOutOfRange, []interface{}{"tags"}},
+		{Write("name", user, 1), ErrTypeMismatch, []interface{}{}},
+		{Write("tags[-2]", user, "b"), ErrIndexOutOfRange, []interface{}{"tags"}},
+		{Write("name", *user, "foo"), ErrNotAddressable, []interface{}{}},
+		{func() error { _, err := Read("name.first", user); return err }(), ErrUnsupportedKind, []interface{}{"name"}},
+		{func() error { _, err := Read("ints.first", user); return err }(), ErrUnsupportedKind, []interface{}{"ints"}},
+		{ApplyPatch(user, []Operation{{Op: "remove", Path: "/tags/3"}}), ErrIndexOutOfRange, []interface{}{"tags", 3}},
+	}
+
+	for _, c := range cases {
+		if assert.IsType(Error{}, c.err) {
+			assert.True(errors.Is(c.err, c.kind), "%s is not %s", c.err, c.kind)
+			assert.Equal(c.path, c.err.(Error).Path, c.err.Error())
+		}
+	}
+
+	err := Write("tags[*]", user, 1)
+	assert.IsType(MultiError{}, err)
+	assert.True(errors.Is(err, ErrTypeMismatch))
+
+	var e Error
+	assert.True(errors.As(err, &e))
+	assert.Equal([]interface{}{"tags"}, e.Path)
+}
+
+func TestErrorCause(t *testing.T) {
+	assert := assert.New(t)
+
+	v := &struct{ Count int }{}
+
+	err := NewAccessor(WithConverter(NewConverter())).Write("count", v, "many")
+	assert.True(errors.Is(err, ErrTypeMismatch))
+	assert.True(errors.Is(err, strconv.ErrSyntax))
+
+	var ne *strconv.NumError
+	if assert.True(errors.As(err, &ne)) {
+		assert.Equal("many", ne.Num)
+	}
+}
@@ -3,7 +3,6 @@ package access
 import (
 	"fmt"
 	"reflect"
-	"strings"
 )
 
 var (
@@ -20,7 +19,11 @@ type FieldWriter interface {
 	SetField(string, interface{}) error
 }
 
-func readField(v reflect.Value, field string, path *Path) (reflect.Value, error) {
+func readField(a *Accessor, v reflect.Value, field string, path *Path) (reflect.Value, error) {
+
+	if !v.IsValid() {
+		return reflect.Value{}, kindError{fmt.Errorf("struct,map or FieldReader instance expected"), ErrNotFound}
+	}
 
 	v = indirectRead(v, fieldReaderInterface)
 
@@ -34,54 +37,59 @@ func readField(v reflect.Value, field string, path *Path) (reflect.Value, error)
 			return fv, err
 		}
 		if path != nil {
-			return path.read(fv)
+			return path.read(a, fv)
 		}
 		return fv, err
 	}
 
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return reflect.Value{}, kindError{fmt.Errorf("struct,map or FieldReader instance expected"), ErrNotFound}
+	}
+
 	switch vt.Kind() {
 	case reflect.Interface:
-		return readField(v.Elem(), field, path)
+		return readField(a, v.Elem(), field, path)
 	case reflect.Map:
 
 		if kk := vt.Key().Kind(); kk != reflect.String {
-			return reflect.Value{}, fmt.Errorf("Map key type is not a string")
+			return reflect.Value{}, kindError{fmt.Errorf("Map key type is not a string"), ErrUnsupportedKind}
 		}
 
 		fv := v.MapIndex(vf)
 
 		if !fv.IsValid() {
-			return fv, fmt.Errorf("Map key not exists")
+			return fv, kindError{fmt.Errorf("Map key not exists"), ErrNotFound}
 		}
 
 		if path == nil {
 			return fv, nil
 		}
 
-		return path.read(fv)
+		return path.read(a, fv)
 
 	case reflect.Struct:
 
-		field = camelcased(field)
-
-		if ft, ok := vt.FieldByName(field); ok {
+		if ft, ok := structField(a, vt, field); ok {
 			fv := v.FieldByIndex(ft.Index)
 
 			if path == nil {
 				return fv, nil
 			}
 
-			return path.read(fv)
+			return path.read(a, fv)
+		}
+
+		if !a.methods {
+			return reflect.Value{}, kindError{fmt.Errorf("Struct has no field `%s`", camelcased(field)), ErrNotFound}
 		}
 
 		if v.CanAddr() {
 			v = v.Addr()
 		}
 
-		methods := []string{field, "Get" + field}
-		for _, m := range methods {
+		for _, prefix := range []string{"", "Get"} {
 
-			if mv := v.MethodByName(m); mv.IsValid() {
+			if mv := structMethod(a, v, field, prefix); mv.IsValid() {
 
 				if mt := mv.Type(); mt.NumIn() != 0 || mt.NumOut() != 1 {
 					continue
@@ -92,17 +100,35 @@ func readField(v reflect.Value, field string, path *Path) (reflect.Value, error)
 				if path == nil {
 					return fv, nil
 				}
-				return path.read(fv)
+				return path.read(a, fv)
 			}
 		}
 
-		return reflect.Value{}, fmt.Errorf("Struct has no field `%s` nor methods %v which satisfy signature func(...) (interface{}) ", field, methods)
+		field = camelcased(field)
+		methods := []string{field, "Get" + field}
+
+		return reflect.Value{}, kindError{fmt.Errorf("Struct has no field `%s` nor methods %v which satisfy signature func(...) (interface{}) ", field, methods), ErrNotFound}
 	default:
-		return reflect.Value{}, fmt.Errorf("struct,map or FieldReader instance expected")
+		return reflect.Value{}, kindError{fmt.Errorf("struct,map or FieldReader instance expected"), ErrUnsupportedKind}
 	}
 }
 
-func writeField(v reflect.Value, field string, path *Path, w reflect.Value, wt reflect.Type) error {
+func writeField(a *Accessor, v reflect.Value, field string, path *Path, w reflect.Value, wt reflect.Type) error {
+
+	// allocate nil pointers, assigning them only when write succeeded
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		if !a.allocate {
+			return allocationError(v.Type())
+		}
+
+		e := reflect.New(v.Type().Elem()).Elem()
+
+		if err := writeField(a, e, field, path, w, wt); err != nil {
+			return err
+		}
+
+		return indirectWrite(a, v, e, e.Type())
+	}
 
 	v = indirectRead(v, fieldWriterInterface)
 
@@ -114,10 +140,10 @@ func writeField(v reflect.Value, field string, path *Path, w reflect.Value, wt r
 				return err
 			}
 			fv := allocateNew(reflect.ValueOf(val))
-			if err := path.write(fv, w, wt); err != nil {
+			if err := path.write(a, fv, w, wt); err != nil {
 				return err
 			}
-			return writeField(v, field, nil, fv, fv.Type())
+			return writeField(a, v, field, nil, fv, fv.Type())
 		}
 
 		return r.SetField(field, w.Interface())
@@ -132,21 +158,34 @@ func writeField(v reflect.Value, field string, path *Path, w reflect.Value, wt r
 		var e reflect.Value
 
 		if v.IsNil() && v.NumMethod() == 0 {
+			if !a.allocate {
+				return allocationError(vt)
+			}
 			e = reflect.ValueOf(map[string]interface{}{})
 		} else {
 			e = allocateNew(v.Elem())
 		}
 
-		if err := writeField(e, field, path, w, wt); err != nil {
+		if err := writeField(a, e, field, path, w, wt); err != nil {
 			return err
 		}
 
-		return indirectWrite(v, e, e.Type())
+		return indirectWrite(a, v, e, e.Type())
 
 	case reflect.Map:
 
 		if kk := vt.Key().Kind(); kk != reflect.String {
-			return fmt.Errorf("Map key type is not a string")
+			return kindError{fmt.Errorf("Map key type is not a string"), ErrUnsupportedKind}
+		}
+
+		if v.IsNil() {
+			if !a.allocate {
+				return allocationError(vt)
+			}
+			if !v.CanSet() {
+				return kindError{fmt.Errorf("got value that couldn't be changed"), ErrNotAddressable}
+			}
+			v.Set(reflect.MakeMap(vt))
 		}
 
 		var fv reflect.Value
@@ -159,14 +198,14 @@ func writeField(v reflect.Value, field string, path *Path, w reflect.Value, wt r
 
 		if path != nil {
 
-			if err := path.write(fv, w, wt); err != nil {
+			if err := path.write(a, fv, w, wt); err != nil {
 				return err
 			}
 
-			return writeField(v, field, nil, fv, fv.Type())
+			return writeField(a, v, field, nil, fv, fv.Type())
 		}
 
-		if err := indirectWrite(fv, w,wt); err != nil {
+		if err := indirectWrite(a, fv, w,wt); err != nil {
 			return err
 		}
 
@@ -175,15 +214,20 @@ func writeField(v reflect.Value, field string, path *Path, w reflect.Value, wt r
 
 	case reflect.Struct:
 
-		field = camelcased(field)
-
-		if ft, ok := vt.FieldByName(field); ok {
+		if ft, ok := structField(a, vt, field); ok {
 			fv := v.FieldByIndex(ft.Index)
 			if path != nil {
-				return path.write(fv, w, wt)
+				return path.write(a, fv, w, wt)
 			}
 
-			return indirectWrite(fv, w,wt)
+			return indirectWrite(a, fv, w,wt)
+		}
+
+		name := field
+		field = camelcased(field)
+
+		if !a.methods {
+			return kindError{fmt.Errorf("Struct has no field `%s`", field), ErrNotFound}
 		}
 
 		if v.CanAddr() {
@@ -192,9 +236,9 @@ func writeField(v reflect.Value, field string, path *Path, w reflect.Value, wt r
 
 		if path != nil {
 			methods := []string{field, "Get" + field}
-			for _, m := range methods {
+			for _, prefix := range []string{"", "Get"} {
 
-				if mv := v.MethodByName(m); mv.IsValid() {
+				if mv := structMethod(a, v, name, prefix); mv.IsValid() {
 
 					if mt := mv.Type(); mt.NumIn() != 0 || mt.NumOut() != 0 {
 						continue
@@ -202,20 +246,20 @@ func writeField(v reflect.Value, field string, path *Path, w reflect.Value, wt r
 
 					fv := mv.Call([]reflect.Value{})[0]
 
-					if err := path.write(fv, w, wt); err != nil {
+					if err := path.write(a, fv, w, wt); err != nil {
 						return err
 					}
-					return writeField(v, field, nil, fv, fv.Type())
+					return writeField(a, v, name, nil, fv, fv.Type())
 				}
 			}
 
-			return fmt.Errorf("Struct has no field `%s` nor methods %v which satisfy signature func() (interface{}) ", field, methods)
+			return kindError{fmt.Errorf("Struct has no field `%s` nor methods %v which satisfy signature func() (interface{}) ", field, methods), ErrNotFound}
 		}
 
 		methods := []string{field, "Set" + field}
-		for _, m := range methods {
+		for n, prefix := range []string{"", "Set"} {
 
-			if mv := v.MethodByName(m); mv.IsValid() {
+			if mv := structMethod(a, v, name, prefix); mv.IsValid() {
 
 				mt := mv.Type()
 				numIn := mt.NumIn()
@@ -224,21 +268,25 @@ func writeField(v reflect.Value, field string, path *Path, w reflect.Value, wt r
 					continue
 				}
 
+				if c := a.converter; c != nil && !wt.AssignableTo(mt.In(0)) {
+					cw, err := c.convert(w, mt.In(0))
+					if err != nil {
+						return err
+					}
+					w, wt = cw, cw.Type()
+				}
+
 				if !wt.ConvertibleTo(mt.In(0)) {
-					return fmt.Errorf("Can't call %s(%s)", m, wt)
+					return kindError{fmt.Errorf("Can't call %s(%s)", methods[n], wt), ErrTypeMismatch}
 				}
 
 				mv.Call([]reflect.Value{w})
 				return nil
 			}
 		}
-		return fmt.Errorf("Struct has no field `%s` nor methods %v which satisfy signature func(interface{},...) (...) ", field, methods)
+		return kindError{fmt.Errorf("Struct has no field `%s` nor methods %v which satisfy signature func(interface{},...) (...) ", field, methods), ErrNotFound}
 
 	default:
-		return fmt.Errorf("struct,map or FieldWriter instance expected")
+		return kindError{fmt.Errorf("struct,map or FieldWriter instance expected"), ErrUnsupportedKind}
 	}
 }
-
-func camelcased(s string) string {
-	return strings.Replace(strings.Title(strings.Replace(s, "_", " ", -1)), " ", "", -1)
-}
@@ -0,0 +1,605 @@
+package access
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Filter is a path element matching every element which satisfies the expression,
+// written as `[?(@.age > 30 && @.active)]`.
+//
+// Expressions support `@` relative paths, string, number, boolean and null literals,
+// comparison operators, `&&`, `||`, `!`, parentheses and functions
+// exists, length, contains, startsWith, endsWith, lower, upper and matches.
+// Bare values are tested for truthiness, so missing values and zero values don't match.
+type Filter struct {
+	Expr string
+	node filterNode
+}
+
+// ParseFilter parses filter expression without surrounding `[?` and `]`.
+func ParseFilter(expr string) (Filter, error) {
+	p := &filterParser{input: expr}
+
+	node, err := p.parse()
+	if err != nil {
+		return Filter{}, err
+	}
+
+	return Filter{strings.TrimSpace(expr), node}, nil
+}
+
+// Match reports whether v satisfies filter expression.
+func (f Filter) Match(v interface{}) bool {
+	return f.match(defaultAccessor(), reflect.ValueOf(v))
+}
+
+func (f Filter) match(a *Accessor, v reflect.Value) bool {
+	if f.node == nil {
+		return false
+	}
+	val, found := f.node.eval(a, v)
+	return found && truthy(val)
+}
+
+// scanFilter finds end of filter expression which starts right after `[?`.
+// It returns expression and number of bytes consumed including closing bracket.
+func scanFilter(s string) (string, int, bool) {
+	depth := 0
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\'':
+			for i++; i < len(s) && s[i] != c; i++ {
+				if s[i] == '\\' {
+					i++
+				}
+			}
+		case '(', '[':
+			depth++
+		case ')':
+			depth--
+		case ']':
+			if depth == 0 {
+				return s[:i], i + 1, true
+			}
+			depth--
+		}
+	}
+
+	return "", 0, false
+}
+
+type filterNode interface {
+	// eval returns value of the node for element v and whether it was found
+	eval(a *Accessor, v reflect.Value) (interface{}, bool)
+}
+
+type filterLiteral struct {
+	value interface{}
+}
+
+func (n *filterLiteral) eval(*Accessor, reflect.Value) (interface{}, bool) {
+	return n.value, true
+}
+
+type filterPath struct {
+	path Path
+}
+
+func (n *filterPath) eval(a *Accessor, v reflect.Value) (interface{}, bool) {
+	rv, err := n.path.read(a, v)
+	if err != nil {
+		return nil, false
+	}
+	return filterValue(rv), true
+}
+
+type filterUnary struct {
+	x filterNode
+}
+
+func (n *filterUnary) eval(a *Accessor, v reflect.Value) (interface{}, bool) {
+	x, found := n.x.eval(a, v)
+	return !(found && truthy(x)), true
+}
+
+type filterBinary struct {
+	op   string
+	x, y filterNode
+}
+
+func (n *filterBinary) eval(a *Accessor, v reflect.Value) (interface{}, bool) {
+	x, xFound := n.x.eval(a, v)
+
+	switch n.op {
+	case "&&":
+		if !(xFound && truthy(x)) {
+			return false, true
+		}
+		y, yFound := n.y.eval(a, v)
+		return yFound && truthy(y), true
+	case "||":
+		if xFound && truthy(x) {
+			return true, true
+		}
+		y, yFound := n.y.eval(a, v)
+		return yFound && truthy(y), true
+	}
+
+	y, yFound := n.y.eval(a, v)
+
+	switch n.op {
+	case "==":
+		return xFound == yFound && (!xFound || equal(x, y)), true
+	case "!=":
+		return !(xFound == yFound && (!xFound || equal(x, y))), true
+	}
+
+	if !xFound || !yFound {
+		return false, true
+	}
+
+	c, ok := compare(x, y)
+	if !ok {
+		return false, true
+	}
+
+	switch n.op {
+	case "<":
+		return c < 0, true
+	case "<=":
+		return c <= 0, true
+	case ">":
+		return c > 0, true
+	case ">=":
+		return c >= 0, true
+	}
+
+	return false, true
+}
+
+type filterCall struct {
+	name string
+	args []filterNode
+	// re is pattern of matches compiled when parsing a literal, nil when it's invalid
+	re *regexp.Regexp
+}
+
+var filterFunctions = map[string]int{
+	"exists":     1,
+	"length":     1,
+	"lower":      1,
+	"upper":      1,
+	"contains":   2,
+	"startsWith": 2,
+	"endsWith":   2,
+	"matches":    2,
+}
+
+func (n *filterCall) eval(a *Accessor, v reflect.Value) (interface{}, bool) {
+	if n.name == "exists" {
+		_, found := n.args[0].eval(a, v)
+		return found, true
+	}
+
+	args := make([]interface{}, len(n.args))
+	for i, arg := range n.args {
+		val, found := arg.eval(a, v)
+		if !found {
+			return nil, false
+		}
+		args[i] = val
+	}
+
+	if n.name == "length" {
+		rv := reflect.ValueOf(args[0])
+		switch rv.Kind() {
+		case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+			return float64(rv.Len()), true
+		}
+		return nil, false
+	}
+
+	strs := make([]string, len(args))
+	for i, arg := range args {
+		s, ok := arg.(string)
+		if !ok {
+			return nil, false
+		}
+		strs[i] = s
+	}
+
+	switch n.name {
+	case "lower":
+		return strings.ToLower(strs[0]), true
+	case "upper":
+		return strings.ToUpper(strs[0]), true
+	case "contains":
+		return strings.Contains(strs[0], strs[1]), true
+	case "startsWith":
+		return strings.HasPrefix(strs[0], strs[1]), true
+	case "endsWith":
+		return strings.HasSuffix(strs[0], strs[1]), true
+	case "matches":
+		re := n.re
+		if _, ok := n.args[1].(*filterLiteral); !ok {
+			re, _ = regexp.Compile(strs[1])
+		}
+		if re == nil {
+			return false, true
+		}
+		return re.MatchString(strs[0]), true
+	}
+
+	return nil, false
+}
+
+// filterValue unwraps pointers and interfaces of value read by path.
+func filterValue(v reflect.Value) interface{} {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+
+	return v.Interface()
+}
+
+func truthy(v interface{}) bool {
+	if v == nil {
+		return false
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.String:
+		return rv.Len() > 0
+	}
+
+	return !rv.IsZero()
+}
+
+// number converts numeric value to float64.
+func number(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+func equal(x, y interface{}) bool {
+	if c, ok := compare(x, y); ok {
+		return c == 0
+	}
+	if bx, ok := x.(bool); ok {
+		by, ok := y.(bool)
+		return ok && bx == by
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+// compare orders numbers and strings, ok is false for values of other types.
+func compare(x, y interface{}) (int, bool) {
+	if nx, ok := number(x); ok {
+		ny, ok := number(y)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case nx < ny:
+			return -1, true
+		case nx > ny:
+			return 1, true
+		}
+		return 0, true
+	}
+
+	sx, ok := stringValue(x)
+	if !ok {
+		return 0, false
+	}
+	sy, ok := stringValue(y)
+	if !ok {
+		return 0, false
+	}
+
+	return strings.Compare(sx, sy), true
+}
+
+func stringValue(v interface{}) (string, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.String {
+		return "", false
+	}
+	return rv.String(), true
+}
+
+type filterParser struct {
+	input string
+	pos   int
+}
+
+func (p *filterParser) error(expected string) error {
+	return &ParseError{p.input, p.pos, expected}
+}
+
+func (p *filterParser) skipSpaces() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+// accept consumes token if input continues with it.
+func (p *filterParser) accept(token string) bool {
+	p.skipSpaces()
+	if strings.HasPrefix(p.input[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) parse() (filterNode, error) {
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	if p.pos != len(p.input) {
+		return nil, p.error("operator")
+	}
+
+	return node, nil
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	x, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("||") {
+		y, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		x = &filterBinary{"||", x, y}
+	}
+
+	return x, nil
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	x, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("&&") {
+		y, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		x = &filterBinary{"&&", x, y}
+	}
+
+	return x, nil
+}
+
+func (p *filterParser) parseNot() (filterNode, error) {
+	p.skipSpaces()
+
+	if strings.HasPrefix(p.input[p.pos:], "!") && !strings.HasPrefix(p.input[p.pos:], "!=") {
+		p.pos++
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &filterUnary{x}, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (filterNode, error) {
+	x, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.accept(op) {
+			y, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			return &filterBinary{op, x, y}, nil
+		}
+	}
+
+	return x, nil
+}
+
+func (p *filterParser) parseValue() (filterNode, error) {
+	p.skipSpaces()
+
+	if p.pos >= len(p.input) {
+		return nil, p.error("value")
+	}
+
+	rest := p.input[p.pos:]
+
+	switch c := rest[0]; {
+	case c == '(':
+		p.pos++
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, p.error("closing parenthesis")
+		}
+		return x, nil
+
+	case c == '@':
+		return p.parsePath()
+
+	case c == '"' || c == '\'':
+		end := 1
+		for ; end < len(rest) && rest[end] != c; end++ {
+			if rest[end] == '\\' {
+				end++
+			}
+		}
+		if end >= len(rest) {
+			return nil, p.error("closing quote")
+		}
+
+		s, err := unquoteKey(rest[:end+1])
+		if err != nil {
+			return nil, p.error("valid escape sequence")
+		}
+
+		p.pos += end + 1
+		return &filterLiteral{s}, nil
+
+	case c == '-' || (c >= '0' && c <= '9'):
+		end := 1
+		for end < len(rest) && strings.IndexByte("0123456789.eE+-", rest[end]) >= 0 {
+			if (rest[end] == '+' || rest[end] == '-') && rest[end-1] != 'e' && rest[end-1] != 'E' {
+				break
+			}
+			end++
+		}
+
+		n, err := strconv.ParseFloat(rest[:end], 64)
+		if err != nil {
+			return nil, p.error("number")
+		}
+
+		p.pos += end
+		return &filterLiteral{n}, nil
+	}
+
+	end := 0
+	for end < len(rest) && (rest[end] == '_' || unicode.IsLetter(rune(rest[end])) || unicode.IsDigit(rune(rest[end]))) {
+		end++
+	}
+
+	name := rest[:end]
+
+	switch name {
+	case "":
+		return nil, p.error("value")
+	case "true":
+		p.pos += end
+		return &filterLiteral{true}, nil
+	case "false":
+		p.pos += end
+		return &filterLiteral{false}, nil
+	case "null", "nil":
+		p.pos += end
+		return &filterLiteral{nil}, nil
+	}
+
+	argc, ok := filterFunctions[name]
+	if !ok {
+		return nil, p.error("function, literal or @ path")
+	}
+
+	p.pos += end
+	if !p.accept("(") {
+		return nil, p.error("opening parenthesis")
+	}
+
+	call := &filterCall{name: name}
+
+	for len(call.args) < argc {
+		if len(call.args) > 0 && !p.accept(",") {
+			return nil, p.error(fmt.Sprintf("%d arguments of %s", argc, name))
+		}
+
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+	}
+
+	if !p.accept(")") {
+		return nil, p.error("closing parenthesis")
+	}
+
+	if lit, ok := call.args[len(call.args)-1].(*filterLiteral); ok && name == "matches" {
+		if pattern, ok := lit.value.(string); ok {
+			call.re, _ = regexp.Compile(pattern)
+		}
+	}
+
+	return call, nil
+}
+
+// parsePath parses `@` followed by dotted or bracketed path elements.
+func (p *filterParser) parsePath() (filterNode, error) {
+	start := p.pos
+	p.pos++ // skip @
+
+	for p.pos < len(p.input) {
+		switch p.input[p.pos] {
+		case '.':
+			p.pos++
+			for p.pos < len(p.input) && (p.input[p.pos] == '_' || p.input[p.pos] == '*' ||
+				unicode.IsLetter(rune(p.input[p.pos])) || unicode.IsDigit(rune(p.input[p.pos]))) {
+				p.pos++
+			}
+			continue
+		case '[':
+			_, n, ok := scanFilter(p.input[p.pos+1:])
+			if !ok {
+				return nil, p.error("closing bracket")
+			}
+			p.pos += n + 1
+			continue
+		}
+		break
+	}
+
+	path, err := Parse(strings.TrimPrefix(p.input[start+1:p.pos], "."))
+	if err != nil {
+		offset := start + 1
+		if e, ok := err.(*ParseError); ok {
+			offset += e.Offset
+			if strings.HasPrefix(p.input[start+1:], ".") {
+				offset++
+			}
+			return nil, &ParseError{p.input, offset, e.Expected}
+		}
+		return nil, err
+	}
+
+	if path.multiIndex() >= 0 {
+		p.pos = start
+		return nil, p.error("concrete path")
+	}
+
+	return &filterPath{path}, nil
+}
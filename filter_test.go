@@ -0,0 +1,154 @@
+package access
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type filterUser struct {
+	Name   string
+	Age    int
+	Active bool
+	Email  *string
+	Tags   []string
+}
+
+func TestFilterParse(t *testing.T) {
+	assert := assert.New(t)
+
+	p := New("users[?(@.age > 30 && @.active)].name")
+	if assert.Len(p, 3) {
+		assert.Equal("users", p[0])
+		assert.Equal("(@.age > 30 && @.active)", p[1].(Filter).Expr)
+		assert.Equal("name", p[2])
+	}
+
+	assert.Equal("users[?(@.age > 30 && @.active)].name", p.String())
+	assert.Equal(`[?(@["a]"] == ")]")][0]`, New(`[?(@["a]"] == ")]")][0]`).String())
+
+	cases := []struct {
+		Selector string
+		Offset   int
+		Expected string
+	}{
+		{"users[?(@.age > 30)", 7, "closing bracket"},
+		{"users[?(@.age > )]", 16, "value"},
+		{"users[?(@.age 30)]", 14, "closing parenthesis"},
+		{"users[?(foo(@))]", 8, "function, literal or @ path"},
+		{"users[?(contains(@.a))]", 20, "2 arguments of contains"},
+		{"users[?(@.a[*])]", 8, "concrete path"},
+		{"users[?(@.a.)]", 12, "field"},
+	}
+
+	for _, c := range cases {
+		_, err := Parse(c.Selector)
+
+		if assert.IsType(&ParseError{}, err, c.Selector) {
+			e := err.(*ParseError)
+			assert.Equal(c.Offset, e.Offset, c.Selector)
+			assert.Equal(c.Expected, e.Expected, c.Selector)
+		}
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	assert := assert.New(t)
+
+	email := "foo@example.com"
+	user := filterUser{"Foo", 35, true, &email, []string{"admin"}}
+
+	cases := []struct {
+		Expr     string
+		Expected bool
+	}{
+		{"@.age > 30", true},
+		{"@.age >= 35 && @.age <= 35", true},
+		{"@.age < 30 || @.active", true},
+		{"!@.active", false},
+		{"!(@.age == 35)", false},
+		{"@.age != 35", false},
+		{"@.name == 'Foo'", true},
+		{`@.name == "Bar"`, false},
+		{"@.name < 'G'", true},
+		{"@.email == 'foo@example.com'", true},
+		{"@.missing == null", false},
+		{"@.missing != 1", true},
+		{"@.missing", false},
+		{"@.tags", true},
+		{"@.tags[0] == 'admin'", true},
+		{"@.tags[-1] == 'admin'", true},
+		{"exists(@.email)", true},
+		{"exists(@.missing)", false},
+		{"length(@.tags) == 1", true},
+		{"length(@.name) > 2", true},
+		{"contains(@.email, '@example')", true},
+		{"startsWith(lower(@.name), 'fo')", true},
+		{"endsWith(upper(@.name), 'OO')", true},
+		{"matches(@.email, '^[a-z]+@')", true},
+		{"matches(@.email, '[')", false},
+		{"@.age == 35.0 && @.age > -1 && @.age < 1e3", true},
+		{"@.active == true", true},
+		{"@.name == 35", false},
+	}
+
+	for _, c := range cases {
+		f, err := ParseFilter(c.Expr)
+		if assert.NoError(err, c.Expr) {
+			assert.Equal(c.Expected, f.Match(user), c.Expr)
+			assert.Equal(c.Expected, f.Match(&user), c.Expr)
+		}
+	}
+
+	f, err := ParseFilter("@ > 2")
+	assert.NoError(err)
+	assert.True(f.Match(3))
+	assert.False(f.Match("3"))
+
+	//literal patterns are compiled once when parsed, others when matched
+	f, err = ParseFilter("matches(@.name, '^f')")
+	if assert.NoError(err) {
+		assert.NotNil(f.node.(*filterCall).re)
+		assert.True(f.Match(map[string]interface{}{"name": "foo"}))
+	}
+
+	f, err = ParseFilter("matches(@.name, @.pattern)")
+	if assert.NoError(err) {
+		assert.Nil(f.node.(*filterCall).re)
+		assert.True(f.Match(map[string]interface{}{"name": "foo", "pattern": "o+$"}))
+		assert.False(f.Match(map[string]interface{}{"name": "foo", "pattern": "["}))
+	}
+}
+
+func TestFilterReadWrite(t *testing.T) {
+	assert := assert.New(t)
+
+	data := map[string]interface{}{
+		"users": []filterUser{
+			{Name: "foo", Age: 35, Active: true},
+			{Name: "bar", Age: 40},
+			{Name: "baz", Age: 20, Active: true},
+		},
+		"groups": map[string]interface{}{
+			"admins": map[string]interface{}{"size": 2},
+			"guests": map[string]interface{}{"size": 10},
+		},
+	}
+
+	matches, err := ReadAll("users[?(@.age > 30 && @.active)].name", data)
+	assert.NoError(err)
+	assert.Equal([]Match{{Path{"users", 0, "name"}, "foo"}}, matches)
+
+	matches, err = ReadAll("users[?(@.age > 30)].name", data)
+	assert.NoError(err)
+	assert.Equal([]Match{{Path{"users", 0, "name"}, "foo"}, {Path{"users", 1, "name"}, "bar"}}, matches)
+
+	matches, err = ReadAll("groups[?(@.size < 5)]", data)
+	assert.NoError(err)
+	assert.Equal([]Match{{Path{"groups", "admins"}, map[string]interface{}{"size": 2}}}, matches)
+
+	_, err = Read("users[?(@.active)]", data)
+	assert.Error(err)
+
+	assert.NoError(Write("users[?(!@.active)].active", &data, true))
+	assert.Equal(true, MustRead("users[1].active", data))
+}
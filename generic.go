@@ -0,0 +1,82 @@
+package access
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Get reads value at path converted to type T. Nil values yield zero T, values of other
+// types are converted when SetConverter enabled it or fail with error matching ErrTypeMismatch.
+func Get[T any](v any, path string) (T, error) {
+
+	var zero T
+
+	p, err := Parse(path)
+	if err != nil {
+		return zero, err
+	}
+
+	a := defaultAccessor()
+
+	val, err := a.readPath(p, v)
+	if err != nil {
+		return zero, err
+	}
+
+	if val == nil {
+		return zero, nil
+	}
+
+	t, ok := val.(T)
+	if !ok {
+		if c := a.converter; c != nil {
+			cv, err := c.Convert(val, reflect.TypeOf(&zero).Elem())
+			if err != nil {
+				return zero, Error{err, p}
+			}
+			return cv.(T), nil
+		}
+		return zero, Error{kindError{fmt.Errorf("Value of type %T is not %s", val, reflect.TypeOf(&zero).Elem()), ErrTypeMismatch}, p}
+	}
+
+	return t, nil
+}
+
+// MustGet reads value at path like Get, panicking on failure.
+func MustGet[T any](v any, path string) T {
+
+	t, err := Get[T](v, path)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+// GetOr reads value at path like Get, returning def when it fails or the value is nil.
+func GetOr[T any](v any, path string, def T) T {
+
+	p, err := Parse(path)
+	if err != nil {
+		return def
+	}
+
+	a := defaultAccessor()
+
+	val, found, err := a.lookupPath(p, v)
+	if err != nil || !found || val == nil {
+		return def
+	}
+
+	t, ok := val.(T)
+	if !ok {
+		if c := a.converter; c != nil {
+			if cv, err := c.Convert(val, reflect.TypeOf(&def).Elem()); err == nil {
+				return cv.(T)
+			}
+		}
+		return def
+	}
+
+	return t
+}
@@ -0,0 +1,79 @@
+package access
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type genericUser struct {
+	Name   string
+	Age    int
+	Friend *genericUser
+	Tags   []string
+	Attrs  map[string]interface{}
+}
+
+func TestGet(t *testing.T) {
+	assert := assert.New(t)
+
+	user := &genericUser{
+		Name:   "foo",
+		Age:    20,
+		Friend: &genericUser{Name: "bar"},
+		Tags:   []string{"a", "b"},
+		Attrs:  map[string]interface{}{"nil": nil, "num": 1.5},
+	}
+
+	name, err := Get[string](user, "name")
+	assert.NoError(err)
+	assert.Equal("foo", name)
+
+	age, err := Get[int](user, "age")
+	assert.NoError(err)
+	assert.Equal(20, age)
+
+	friend, err := Get[*genericUser](user, "friend")
+	assert.NoError(err)
+	assert.Same(user.Friend, friend)
+
+	tags, err := Get[[]string](user, "tags")
+	assert.NoError(err)
+	assert.Equal([]string{"a", "b"}, tags)
+
+	num, err := Get[interface{}](user, "attrs.num")
+	assert.NoError(err)
+	assert.Equal(1.5, num)
+
+	nilFriend, err := Get[*genericUser](user, "friend.friend")
+	assert.NoError(err)
+	assert.Nil(nilFriend)
+
+	empty, err := Get[string](user, "attrs.nil")
+	assert.NoError(err)
+	assert.Equal("", empty)
+
+	_, err = Get[int](user, "name")
+	assert.True(errors.Is(err, ErrTypeMismatch))
+	if assert.IsType(Error{}, err) {
+		assert.Equal([]interface{}{"name"}, err.(Error).Path)
+		assert.Equal("Value of type string is not int at `name`", err.Error())
+	}
+
+	_, err = Get[string](user, "attrs.missing")
+	assert.True(errors.Is(err, ErrNotFound))
+
+	_, err = Get[string](user, "name[")
+	assert.IsType(&ParseError{}, err)
+
+	assert.Equal("bar", MustGet[string](user, "friend.name"))
+	assert.Panics(func() { MustGet[int](user, "friend.name") })
+	assert.Panics(func() { MustGet[int](user, "friend.missing") })
+
+	assert.Equal("b", GetOr(user, "tags[-1]", "z"))
+	assert.Equal("z", GetOr(user, "tags[2]", "z"))
+	assert.Equal("z", GetOr(user, "attrs.nil", "z"))
+	assert.Equal(0, GetOr(user, "attrs.num", 0))
+	assert.Equal(2.5, GetOr(user, "attrs.none", 2.5))
+	assert.Equal(1, GetOr(user, "name[", 1))
+}
@@ -19,29 +19,88 @@ type IndexWriter interface {
 	SetIndex(int, interface{}) error
 }
 
-func readIndex(v reflect.Value, index int, path *Path) (reflect.Value, error) {
+// IndexAppender is implemented by IndexWriter instances which support appending through `[-]`.
+type IndexAppender interface {
+	IndexWriter
+	AppendIndex(interface{}) error
+}
+
+// Append is a path element appending written value to a slice, written as `[-]`.
+type Append struct{}
+
+// Lener is implemented by IndexReader instances which know their length,
+// so that negative indices could be resolved before calling Index or SetIndex.
+type Lener interface {
+	Len() int
+}
+
+// resolveIndex converts negative index counting from the end into absolute one.
+func resolveIndex(index int, length int) (int, error) {
+	if index >= 0 {
+		return index, nil
+	}
+
+	if index+length < 0 {
+		return index, kindError{fmt.Errorf("Index %d out of range %d.", index, length), ErrIndexOutOfRange}
+	}
+
+	return index + length, nil
+}
+
+// resolveReaderIndex resolves negative index for IndexReader instances using Lener.
+func resolveReaderIndex(r IndexReader, index int) (int, error) {
+	if index >= 0 {
+		return index, nil
+	}
+
+	l, ok := r.(Lener)
+	if !ok {
+		return index, kindError{fmt.Errorf("Negative index %d requires %T to implement Len() int", index, r), ErrUnsupportedKind}
+	}
+
+	return resolveIndex(index, l.Len())
+}
+
+func readIndex(a *Accessor, v reflect.Value, index int, path *Path) (reflect.Value, error) {
+	if !v.IsValid() {
+		return reflect.Value{}, kindError{fmt.Errorf("slice, array or IndexWriter instance expected"), ErrNotFound}
+	}
+
 	v = indirectRead(v, indexReaderInterface)
 	vt := v.Type()
 
 	if r, ok := v.Interface().(IndexReader); ok {
+		index, err := resolveReaderIndex(r, index)
+		if err != nil {
+			return reflect.Value{}, err
+		}
 		val, err := r.Index(index)
 		iv := reflect.ValueOf(val)
 		if err != nil {
 			return iv, err
 		}
 		if path != nil {
-			return path.read(iv)
+			return path.read(a, iv)
 		}
 		return iv, err
 	}
 
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return reflect.Value{}, kindError{fmt.Errorf("slice, array or IndexWriter instance expected"), ErrNotFound}
+	}
+
 	switch vt.Kind() {
 	case reflect.Interface:
-		return readIndex(v.Elem(), index, path)
+		return readIndex(a, v.Elem(), index, path)
 	case reflect.Array, reflect.Slice:
 
+		index, err := resolveIndex(index, v.Len())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
 		if index >= v.Len() {
-			return reflect.Value{}, fmt.Errorf("Index %d out of range %d.", index, v.Len())
+			return reflect.Value{}, kindError{fmt.Errorf("Index %d out of range %d.", index, v.Len()), ErrIndexOutOfRange}
 		}
 
 		iv := v.Index(index)
@@ -50,18 +109,38 @@ func readIndex(v reflect.Value, index int, path *Path) (reflect.Value, error) {
 			return iv, nil
 		}
 
-		return path.read(iv)
+		return path.read(a, iv)
 	default:
-		return reflect.Value{}, fmt.Errorf("slice, array or IndexWriter instance expected")
+		return reflect.Value{}, kindError{fmt.Errorf("slice, array or IndexWriter instance expected"), ErrUnsupportedKind}
 	}
 }
 
-func writeIndex(v reflect.Value, index int, path *Path, w reflect.Value, wt reflect.Type) error {
+func writeIndex(a *Accessor, v reflect.Value, index int, path *Path, w reflect.Value, wt reflect.Type) error {
+
+	// allocate nil pointers, assigning them only when write succeeded
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		if !a.allocate {
+			return allocationError(v.Type())
+		}
+
+		e := reflect.New(v.Type().Elem()).Elem()
+
+		if err := writeIndex(a, e, index, path, w, wt); err != nil {
+			return err
+		}
+
+		return indirectWrite(a, v, e, e.Type())
+	}
 
 	v = indirectRead(v, indexWriterInterface)
 
 	if r, ok := v.Interface().(IndexWriter); ok {
 
+		index, err := resolveReaderIndex(r, index)
+		if err != nil {
+			return err
+		}
+
 		if path != nil {
 			val, err := r.Index(index)
 			if err != nil {
@@ -69,11 +148,11 @@ func writeIndex(v reflect.Value, index int, path *Path, w reflect.Value, wt refl
 			}
 
 			iv := allocateNew(reflect.ValueOf(val))
-			if err := path.write(iv, w, wt); err != nil {
+			if err := path.write(a, iv, w, wt); err != nil {
 				return err
 			}
 
-			return writeIndex(v, index, nil, iv, iv.Type())
+			return writeIndex(a, v, index, nil, iv, iv.Type())
 		}
 
 		r.SetIndex(index, w.Interface())
@@ -88,20 +167,31 @@ func writeIndex(v reflect.Value, index int, path *Path, w reflect.Value, wt refl
 		var e reflect.Value
 
 		if v.IsNil() && v.NumMethod() == 0 {
+			if !a.allocate {
+				return allocationError(vt)
+			}
+			if index < 0 {
+				return kindError{fmt.Errorf("Index %d out of range %d.", index, 0), ErrIndexOutOfRange}
+			}
 			array := make([]interface{}, index+1)
 			e = reflect.ValueOf(&array).Elem()
 		} else {
 			e = allocateNew(v.Elem())
 		}
 
-		if err := writeIndex(e, index, path, w, wt); err != nil {
+		if err := writeIndex(a, e, index, path, w, wt); err != nil {
 			return err
 		}
 
-		return indirectWrite(v, e,e.Type())
+		return indirectWrite(a, v, e,e.Type())
 
 	case reflect.Array, reflect.Slice:
 
+		index, err := resolveIndex(index, v.Len())
+		if err != nil {
+			return err
+		}
+
 		var iv reflect.Value
 
 		if path != nil {
@@ -112,11 +202,11 @@ func writeIndex(v reflect.Value, index int, path *Path, w reflect.Value, wt refl
 				iv = allocateNew(v.Index(index))
 			}
 
-			if err := path.write(iv, w, wt); err != nil {
+			if err := path.write(a, iv, w, wt); err != nil {
 				return err
 			}
 
-			return writeIndex(v, index, nil, iv, iv.Type())
+			return writeIndex(a, v, index, nil, iv, iv.Type())
 		}
 
 		if index >= v.Len() {
@@ -125,7 +215,7 @@ func writeIndex(v reflect.Value, index int, path *Path, w reflect.Value, wt refl
 			iv = allocateNew(v.Index(index))
 		}
 
-		if err := indirectWrite(iv, w,wt); err != nil {
+		if err := indirectWrite(a, iv, w,wt); err != nil {
 			return err
 		}
 
@@ -145,13 +235,101 @@ func writeIndex(v reflect.Value, index int, path *Path, w reflect.Value, wt refl
 		}
 
 		if index >= v.Len() {
-			return fmt.Errorf("Index %d out of range %d.", index, v.Len())
+			return kindError{fmt.Errorf("Index %d out of range %d.", index, v.Len()), ErrIndexOutOfRange}
 		}
 
 		v.Index(index).Set(iv)
 
 		return nil
 	default:
-		return fmt.Errorf("slice, array or IndexWriter instance expected")
+		return kindError{fmt.Errorf("slice, array or IndexWriter instance expected"), ErrUnsupportedKind}
+	}
+}
+
+func writeAppend(a *Accessor, v reflect.Value, path *Path, w reflect.Value, wt reflect.Type) error {
+
+	// allocate nil pointers, assigning them only when append succeeded
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		if !a.allocate {
+			return allocationError(v.Type())
+		}
+
+		e := reflect.New(v.Type().Elem()).Elem()
+
+		if err := writeAppend(a, e, path, w, wt); err != nil {
+			return err
+		}
+
+		return indirectWrite(a, v, e, e.Type())
+	}
+
+	v = indirectRead(v, indexWriterInterface)
+
+	if r, ok := v.Interface().(IndexWriter); ok {
+
+		if path != nil {
+			var val interface{}
+			iv := reflect.ValueOf(&val).Elem()
+
+			if err := path.write(a, iv, w, wt); err != nil {
+				return err
+			}
+
+			w = iv.Elem()
+		}
+
+		var val interface{}
+		if w.IsValid() {
+			val = w.Interface()
+		}
+
+		if ap, ok := r.(IndexAppender); ok {
+			return ap.AppendIndex(val)
+		}
+
+		if l, ok := r.(Lener); ok {
+			return r.SetIndex(l.Len(), val)
+		}
+
+		return kindError{fmt.Errorf("%T must implement IndexAppender or Lener to append", r), ErrUnsupportedKind}
+	}
+
+	vt := v.Type()
+
+	switch vt.Kind() {
+	case reflect.Interface:
+		var e reflect.Value
+
+		if v.IsNil() && v.NumMethod() == 0 {
+			if !a.allocate {
+				return allocationError(vt)
+			}
+			array := []interface{}{}
+			e = reflect.ValueOf(&array).Elem()
+		} else {
+			e = allocateNew(v.Elem())
+		}
+
+		if err := writeAppend(a, e, path, w, wt); err != nil {
+			return err
+		}
+
+		return indirectWrite(a, v, e, e.Type())
+
+	case reflect.Slice:
+
+		iv := reflect.New(vt.Elem()).Elem()
+
+		if path != nil {
+			if err := path.write(a, iv, w, wt); err != nil {
+				return err
+			}
+		} else if err := indirectWrite(a, iv, w, wt); err != nil {
+			return err
+		}
+
+		return indirectWrite(a, v, reflect.Append(v, iv), vt)
+	default:
+		return kindError{fmt.Errorf("slice or IndexWriter instance expected"), ErrUnsupportedKind}
 	}
 }
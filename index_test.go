@@ -143,3 +143,117 @@ func TestIndexWrite(t *testing.T) {
 	assert.Equal("test", MustRead(0, anything))
 
 }
+
+type LenIndexes struct {
+	Indexes
+}
+
+func (i LenIndexes) Len() int {
+	return len(i.Slice)
+}
+
+func TestNegativeIndex(t *testing.T) {
+
+	assert := assert.New(t)
+
+	assert.Equal(Path{"items", -1}, New("items[-1]"))
+	assert.Equal("items[-2]", New("items[-2]").String())
+	assert.Equal(Path{-1}, New(-1))
+	assert.Panics(func() { New("items[--1]") })
+
+	p := [3]string{"foo", "bar", "baz"}
+	s := p[:]
+
+	assert.Equal("baz", MustRead(-1, p))
+	assert.Equal("foo", MustRead(-3, s))
+	_, err := Read(-4, s)
+	assert.Error(err)
+
+	data := map[string]interface{}{"items": []interface{}{"a", map[string]interface{}{"name": "b"}}}
+	assert.Equal("b", MustRead("items[-1].name", data))
+
+	assert.NoError(Write("items[-1].name", &data, "c"))
+	assert.Equal("c", MustRead("items[1].name", data))
+
+	assert.NoError(Write(-1, &p, "qux"))
+	assert.Equal("qux", p[2])
+
+	assert.NoError(Write(-2, &s, "quux"))
+	assert.Equal("quux", s[1])
+
+	assert.Error(Write(-4, &s, "quux"))
+
+	var anything interface{}
+	assert.Error(Write(-1, &anything, "foo"))
+
+	//IndexReader with known length
+	li := LenIndexes{Indexes{[]string{"foo", "bar"}}}
+	assert.Equal("bar", MustRead(-1, li))
+	assert.NoError(Write(-2, &li, "qux"))
+	assert.Equal("qux", li.Slice[0])
+
+	_, err = Read(-3, li)
+	assert.Error(err)
+
+	//IndexReader without known length
+	_, err = Read(-1, Indexes{[]string{"foo"}})
+	assert.Error(err)
+	assert.Error(Write(-1, &Indexes{[]string{"foo"}}, "bar"))
+}
+
+type AppendIndexes struct {
+	Indexes
+}
+
+func (i *AppendIndexes) AppendIndex(v interface{}) error {
+	return Write("[-]", &i.Slice, v)
+}
+
+func TestAppend(t *testing.T) {
+
+	assert := assert.New(t)
+
+	assert.Equal(Path{"list", Append{}, "name"}, New("list[-].name"))
+	assert.Equal("list[-].name", New("list[-].name").String())
+
+	_, err := Read("[-]", []string{"foo"})
+	assert.Error(err)
+
+	s := []string{"foo"}
+	assert.NoError(Write("[-]", &s, "bar"))
+	assert.Equal([]string{"foo", "bar"}, s)
+
+	a := [1]string{"foo"}
+	assert.Error(Write("[-]", &a, "bar"))
+
+	//nil pointers and interfaces are allocated
+	var ps *[]string
+	assert.NoError(Write("[-]", &ps, "foo"))
+	assert.Equal([]string{"foo"}, *ps)
+
+	var anything interface{}
+	assert.NoError(Write("[-]", &anything, "foo"))
+	assert.NoError(Write("[-].name", &anything, "bar"))
+	assert.Equal("foo", MustRead(0, anything))
+	assert.Equal("bar", MustRead("[1].name", anything))
+
+	data := map[string][]int{}
+	assert.NoError(Write("list[-]", &data, 1))
+	assert.NoError(Write("list[-]", &data, 2))
+	assert.Equal([]int{1, 2}, data["list"])
+
+	assert.Error(Write("list[-]", &data, "foo"))
+	assert.Equal([]int{1, 2}, data["list"])
+
+	//IndexAppender
+	ai := &AppendIndexes{Indexes{[]string{"foo"}}}
+	assert.NoError(Write("[-]", ai, "bar"))
+	assert.Equal([]string{"foo", "bar"}, ai.Slice)
+
+	//IndexWriter with Lener falls back to SetIndex(Len())
+	li := &LenIndexes{Indexes{[]string{"foo"}}}
+	assert.NoError(Write("[-]", li, "bar"))
+	assert.Equal([]string{"foo", "bar"}, li.Slice)
+
+	assert.Error(Write("[-]", &Indexes{[]string{"foo"}}, "bar"))
+}
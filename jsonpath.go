@@ -0,0 +1,1135 @@
+package access
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// JSONPath is a query parsed from RFC 9535 JSONPath expression like `$.store.book[*].author`.
+// Queries are evaluated over arbitrary Go values, resolving struct fields, maps,
+// FieldReader and IndexReader instances the same way Path does.
+type JSONPath struct {
+	Expr     string
+	accessor *Accessor
+	query    *jsonPathQuery
+}
+
+// ParseJSONPath parses RFC 9535 JSONPath expression using the default instance.
+func ParseJSONPath(expr string) (*JSONPath, error) {
+	return defaultAccessor().ParseJSONPath(expr)
+}
+
+// ParseJSONPath parses RFC 9535 JSONPath expression, which is evaluated according to options of a.
+func (a *Accessor) ParseJSONPath(expr string) (*JSONPath, error) {
+	p := &jsonPathParser{input: expr}
+
+	if !p.accept("$") {
+		return nil, p.error("root identifier `$`")
+	}
+
+	q, err := p.parseSegments(true)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos != len(p.input) {
+		return nil, p.error("segment")
+	}
+
+	if a.maxDepth > 0 && len(q.segments) > a.maxDepth {
+		return nil, Error{kindError{fmt.Errorf("Query of %d segments exceeds max depth %d", len(q.segments), a.maxDepth), ErrMaxDepth}, []interface{}{}}
+	}
+
+	return &JSONPath{expr, a, q}, nil
+}
+
+// Select returns every node of v matched by the query.
+func (q *JSONPath) Select(v interface{}) []Match {
+	root := reflect.ValueOf(v)
+
+	nodes := q.query.nodes(q.accessor, root, root)
+	matches := make([]Match, len(nodes))
+
+	for i, n := range nodes {
+		var val interface{}
+		if n.value.IsValid() && n.value.CanInterface() {
+			val = n.value.Interface()
+		}
+		matches[i] = Match{n.path, val}
+	}
+
+	return matches
+}
+
+func (q *JSONPath) String() string {
+	return q.Expr
+}
+
+// NormalizedPath formats concrete path as RFC 9535 normalized path like `$['a'][0]`.
+func (p Path) NormalizedPath() string {
+	var b strings.Builder
+
+	b.WriteString("$")
+
+	for _, e := range p {
+		switch s := e.(type) {
+		case string:
+			b.WriteString("['")
+			for _, r := range s {
+				switch r {
+				case '\b':
+					b.WriteString(`\b`)
+				case '\f':
+					b.WriteString(`\f`)
+				case '\n':
+					b.WriteString(`\n`)
+				case '\r':
+					b.WriteString(`\r`)
+				case '\t':
+					b.WriteString(`\t`)
+				case '\'':
+					b.WriteString(`\'`)
+				case '\\':
+					b.WriteString(`\\`)
+				default:
+					if r < 0x20 {
+						fmt.Fprintf(&b, `\u%04x`, r)
+					} else {
+						b.WriteRune(r)
+					}
+				}
+			}
+			b.WriteString("']")
+		case int:
+			fmt.Fprintf(&b, "[%d]", s)
+		default:
+			fmt.Fprintf(&b, "[%v]", s)
+		}
+	}
+
+	return b.String()
+}
+
+type jsonPathNode struct {
+	path  Path
+	value reflect.Value
+}
+
+func childPath(path Path, e interface{}) Path {
+	cp := make(Path, 0, len(path)+1)
+	cp = append(cp, path...)
+	return append(cp, e)
+}
+
+// jsonArray returns length of slice, array or IndexReader with Len.
+func jsonArray(v reflect.Value) (int, bool) {
+	if !v.IsValid() {
+		return 0, false
+	}
+
+	v = indirectRead(v, indexReaderInterface)
+
+	if r, ok := v.Interface().(IndexReader); ok {
+		if l, ok := r.(Lener); ok {
+			return l.Len(), true
+		}
+	}
+
+	for (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		return v.Len(), true
+	}
+
+	return 0, false
+}
+
+type jsonPathQuery struct {
+	absolute bool
+	segments []jsonPathSegment
+}
+
+func (q *jsonPathQuery) nodes(a *Accessor, root, cur reflect.Value) []jsonPathNode {
+	start := cur
+	if q.absolute {
+		start = root
+	}
+
+	nodes := []jsonPathNode{{Path{}, start}}
+
+	for _, s := range q.segments {
+		out := []jsonPathNode{}
+
+		for _, n := range nodes {
+			if !s.descendant {
+				s.apply(a, root, n.path, n.value, &out)
+				continue
+			}
+
+			paths, values := descendants(a, n.value, nil)
+			for i, p := range paths {
+				cp := make(Path, 0, len(n.path)+len(p))
+				cp = append(cp, n.path...)
+				cp = append(cp, p...)
+				s.apply(a, root, cp, values[i], &out)
+			}
+		}
+
+		nodes = out
+	}
+
+	return nodes
+}
+
+// singular reports whether query produces at most one node.
+func (q *jsonPathQuery) singular() bool {
+	for _, s := range q.segments {
+		if s.descendant || len(s.selectors) != 1 {
+			return false
+		}
+
+		switch s.selectors[0].(type) {
+		case jsonPathName, jsonPathIndex:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func (q *jsonPathQuery) value(a *Accessor, root, cur reflect.Value) (interface{}, bool) {
+	nodes := q.nodes(a, root, cur)
+	if len(nodes) != 1 {
+		return nil, false
+	}
+	return filterValue(nodes[0].value), true
+}
+
+func (q *jsonPathQuery) test(a *Accessor, root, cur reflect.Value) bool {
+	return len(q.nodes(a, root, cur)) > 0
+}
+
+type jsonPathSegment struct {
+	descendant bool
+	selectors  []jsonPathSelector
+}
+
+func (s jsonPathSegment) apply(a *Accessor, root reflect.Value, path Path, v reflect.Value, out *[]jsonPathNode) {
+	for _, sel := range s.selectors {
+		sel.apply(a, root, path, v, out)
+	}
+}
+
+type jsonPathSelector interface {
+	apply(a *Accessor, root reflect.Value, path Path, v reflect.Value, out *[]jsonPathNode)
+}
+
+type jsonPathName string
+
+func (s jsonPathName) apply(a *Accessor, root reflect.Value, path Path, v reflect.Value, out *[]jsonPathNode) {
+	if _, ok := jsonArray(v); ok {
+		return
+	}
+
+	cv, err := readField(a, v, string(s), nil)
+	if err != nil {
+		return
+	}
+
+	*out = append(*out, jsonPathNode{childPath(path, string(s)), cv})
+}
+
+type jsonPathIndex int
+
+func (s jsonPathIndex) apply(a *Accessor, root reflect.Value, path Path, v reflect.Value, out *[]jsonPathNode) {
+	l, ok := jsonArray(v)
+	if !ok {
+		return
+	}
+
+	i := int(s)
+	if i < 0 {
+		i += l
+	}
+
+	if i < 0 || i >= l {
+		return
+	}
+
+	cv, err := readIndex(a, v, i, nil)
+	if err != nil {
+		return
+	}
+
+	*out = append(*out, jsonPathNode{childPath(path, i), cv})
+}
+
+type jsonPathWildcard struct{}
+
+func (s jsonPathWildcard) apply(a *Accessor, root reflect.Value, path Path, v reflect.Value, out *[]jsonPathNode) {
+	keys, values, err := elements(a, v)
+	if err != nil {
+		return
+	}
+
+	for i, key := range keys {
+		*out = append(*out, jsonPathNode{childPath(path, key), values[i]})
+	}
+}
+
+type jsonPathSlice struct {
+	Range
+}
+
+func (s jsonPathSlice) apply(a *Accessor, root reflect.Value, path Path, v reflect.Value, out *[]jsonPathNode) {
+	l, ok := jsonArray(v)
+	if !ok {
+		return
+	}
+
+	// zero step selects nothing instead of failing
+	indices, err := s.indices(l)
+	if err != nil {
+		return
+	}
+
+	for _, i := range indices {
+		if cv, err := readIndex(a, v, i, nil); err == nil {
+			*out = append(*out, jsonPathNode{childPath(path, i), cv})
+		}
+	}
+}
+
+type jsonPathFilter struct {
+	expr jsonPathLogical
+}
+
+func (s jsonPathFilter) apply(a *Accessor, root reflect.Value, path Path, v reflect.Value, out *[]jsonPathNode) {
+	keys, values, err := elements(a, v)
+	if err != nil {
+		return
+	}
+
+	for i, key := range keys {
+		if s.expr.test(a, root, values[i]) {
+			*out = append(*out, jsonPathNode{childPath(path, key), values[i]})
+		}
+	}
+}
+
+type jsonPathLogical interface {
+	test(a *Accessor, root, cur reflect.Value) bool
+}
+
+type jsonPathValue interface {
+	value(a *Accessor, root, cur reflect.Value) (interface{}, bool)
+}
+
+type jsonPathNodes interface {
+	nodes(a *Accessor, root, cur reflect.Value) []jsonPathNode
+}
+
+type jsonPathOr struct {
+	x, y jsonPathLogical
+}
+
+func (e *jsonPathOr) test(a *Accessor, root, cur reflect.Value) bool {
+	return e.x.test(a, root, cur) || e.y.test(a, root, cur)
+}
+
+type jsonPathAnd struct {
+	x, y jsonPathLogical
+}
+
+func (e *jsonPathAnd) test(a *Accessor, root, cur reflect.Value) bool {
+	return e.x.test(a, root, cur) && e.y.test(a, root, cur)
+}
+
+type jsonPathNot struct {
+	x jsonPathLogical
+}
+
+func (e *jsonPathNot) test(a *Accessor, root, cur reflect.Value) bool {
+	return !e.x.test(a, root, cur)
+}
+
+type jsonPathLiteral struct {
+	v interface{}
+}
+
+func (e *jsonPathLiteral) value(*Accessor, reflect.Value, reflect.Value) (interface{}, bool) {
+	return e.v, true
+}
+
+type jsonPathComparison struct {
+	op   string
+	x, y jsonPathValue
+}
+
+func (e *jsonPathComparison) test(a *Accessor, root, cur reflect.Value) bool {
+	x, xFound := e.x.value(a, root, cur)
+	y, yFound := e.y.value(a, root, cur)
+
+	eq := func() bool {
+		if !xFound || !yFound {
+			return xFound == yFound
+		}
+		return equal(x, y)
+	}
+
+	less := func(x, y interface{}) bool {
+		if !xFound || !yFound {
+			return false
+		}
+		c, ok := compare(x, y)
+		return ok && c < 0
+	}
+
+	switch e.op {
+	case "==":
+		return eq()
+	case "!=":
+		return !eq()
+	case "<":
+		return less(x, y)
+	case "<=":
+		return less(x, y) || eq()
+	case ">":
+		return less(y, x)
+	case ">=":
+		return less(y, x) || eq()
+	}
+
+	return false
+}
+
+type jsonPathType int
+
+const (
+	jsonPathValueType jsonPathType = iota
+	jsonPathLogicalType
+	jsonPathNodesType
+)
+
+var jsonPathFunctions = map[string]struct {
+	params []jsonPathType
+	result jsonPathType
+}{
+	"length": {[]jsonPathType{jsonPathValueType}, jsonPathValueType},
+	"count":  {[]jsonPathType{jsonPathNodesType}, jsonPathValueType},
+	"match":  {[]jsonPathType{jsonPathValueType, jsonPathValueType}, jsonPathLogicalType},
+	"search": {[]jsonPathType{jsonPathValueType, jsonPathValueType}, jsonPathLogicalType},
+	"value":  {[]jsonPathType{jsonPathNodesType}, jsonPathValueType},
+}
+
+type jsonPathFunction struct {
+	name string
+	args []interface{}
+}
+
+func (e *jsonPathFunction) value(a *Accessor, root, cur reflect.Value) (interface{}, bool) {
+	switch e.name {
+	case "length":
+		x, found := e.args[0].(jsonPathValue).value(a, root, cur)
+		if !found || x == nil {
+			return nil, false
+		}
+
+		if s, ok := x.(string); ok {
+			return utf8.RuneCountInString(s), true
+		}
+
+		rv := reflect.ValueOf(x)
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return rv.Len(), true
+		case reflect.Struct:
+			keys, _, _ := elements(a, rv)
+			return len(keys), true
+		}
+		return nil, false
+
+	case "count":
+		return len(e.args[0].(jsonPathNodes).nodes(a, root, cur)), true
+
+	case "value":
+		nodes := e.args[0].(jsonPathNodes).nodes(a, root, cur)
+		if len(nodes) != 1 {
+			return nil, false
+		}
+		return filterValue(nodes[0].value), true
+	}
+
+	return nil, false
+}
+
+func (e *jsonPathFunction) test(a *Accessor, root, cur reflect.Value) bool {
+	x, xFound := e.args[0].(jsonPathValue).value(a, root, cur)
+	y, yFound := e.args[1].(jsonPathValue).value(a, root, cur)
+
+	s, ok := x.(string)
+	re, rok := y.(string)
+
+	if !xFound || !yFound || !ok || !rok {
+		return false
+	}
+
+	if e.name == "match" {
+		re = "^(?:" + re + ")$"
+	}
+
+	compiled, err := regexp.Compile(iregexp(re))
+	if err != nil {
+		return false
+	}
+
+	return compiled.MatchString(s)
+}
+
+// iregexp converts RFC 9485 I-Regexp into Go syntax, where `.` doesn't match line breaks.
+func iregexp(re string) string {
+	var (
+		b     strings.Builder
+		class bool
+	)
+
+	for i := 0; i < len(re); i++ {
+		switch c := re[i]; {
+		case c == '\\' && i+1 < len(re):
+			b.WriteByte(c)
+			b.WriteByte(re[i+1])
+			i++
+		case c == '[':
+			class = true
+			b.WriteByte(c)
+		case c == ']':
+			class = false
+			b.WriteByte(c)
+		case c == '.' && !class:
+			b.WriteString(`[^\n\r]`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+type jsonPathParser struct {
+	input string
+	pos   int
+}
+
+func (p *jsonPathParser) error(expected string) error {
+	return &ParseError{p.input, p.pos, expected}
+}
+
+func (p *jsonPathParser) peek() byte {
+	if p.pos < len(p.input) {
+		return p.input[p.pos]
+	}
+	return 0
+}
+
+func (p *jsonPathParser) accept(token string) bool {
+	if strings.HasPrefix(p.input[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *jsonPathParser) skipSpaces() {
+	for p.pos < len(p.input) && strings.IndexByte(" \t\n\r", p.input[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// parseSegments parses segments following root or current node identifier.
+func (p *jsonPathParser) parseSegments(absolute bool) (*jsonPathQuery, error) {
+	q := &jsonPathQuery{absolute: absolute}
+
+	for {
+		start := p.pos
+		p.skipSpaces()
+
+		var (
+			seg jsonPathSegment
+			err error
+		)
+
+		switch {
+		case p.accept(".."):
+			seg.descendant = true
+			if p.peek() == '[' {
+				seg.selectors, err = p.parseBracketed()
+			} else {
+				seg.selectors, err = p.parseShorthand()
+			}
+		case p.accept("."):
+			seg.selectors, err = p.parseShorthand()
+		case p.peek() == '[':
+			seg.selectors, err = p.parseBracketed()
+		default:
+			p.pos = start
+			return q, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		q.segments = append(q.segments, seg)
+	}
+}
+
+func isNameFirst(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
+		(r >= 0x80 && r <= 0xD7FF) || (r >= 0xE000 && r <= 0x10FFFF)
+}
+
+// parseShorthand parses `*` or member name following dot.
+func (p *jsonPathParser) parseShorthand() ([]jsonPathSelector, error) {
+	if p.accept("*") {
+		return []jsonPathSelector{jsonPathWildcard{}}, nil
+	}
+
+	start := p.pos
+
+	for p.pos < len(p.input) {
+		r, n := utf8.DecodeRuneInString(p.input[p.pos:])
+		if !isNameFirst(r) && !(p.pos > start && r >= '0' && r <= '9') {
+			break
+		}
+		p.pos += n
+	}
+
+	if p.pos == start {
+		return nil, p.error("member name or `*`")
+	}
+
+	return []jsonPathSelector{jsonPathName(p.input[start:p.pos])}, nil
+}
+
+// parseBracketed parses comma separated selectors in brackets.
+func (p *jsonPathParser) parseBracketed() ([]jsonPathSelector, error) {
+	p.pos++ // skip [
+
+	selectors := []jsonPathSelector{}
+
+	for {
+		p.skipSpaces()
+
+		sel, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, sel)
+
+		p.skipSpaces()
+
+		if p.accept("]") {
+			return selectors, nil
+		}
+
+		if !p.accept(",") {
+			return nil, p.error("`,` or `]`")
+		}
+	}
+}
+
+func (p *jsonPathParser) parseSelector() (jsonPathSelector, error) {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return jsonPathName(s), nil
+
+	case c == '*':
+		p.pos++
+		return jsonPathWildcard{}, nil
+
+	case c == '?':
+		p.pos++
+		p.skipSpaces()
+
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return jsonPathFilter{expr}, nil
+	}
+
+	var (
+		r     Range
+		err   error
+		start = p.pos
+	)
+
+	if c := p.peek(); c != ':' {
+		if r.Start, err = p.parseInt(); err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+	}
+
+	if !p.accept(":") {
+		if r.Start == nil {
+			p.pos = start
+			return nil, p.error("selector")
+		}
+		return jsonPathIndex(*r.Start), nil
+	}
+
+	p.skipSpaces()
+
+	if c := p.peek(); c == '-' || (c >= '0' && c <= '9') {
+		if r.End, err = p.parseInt(); err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+	}
+
+	if p.accept(":") {
+		p.skipSpaces()
+
+		if c := p.peek(); c == '-' || (c >= '0' && c <= '9') {
+			if r.Step, err = p.parseInt(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return jsonPathSlice{r}, nil
+}
+
+// maxJSONInt is the largest integer exactly representable in I-JSON.
+const maxJSONInt = 1<<53 - 1
+
+func (p *jsonPathParser) parseInt() (*int, error) {
+	start := p.pos
+
+	p.accept("-")
+
+	digits := p.pos
+	for p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
+		p.pos++
+	}
+
+	s := p.input[start:p.pos]
+
+	if p.pos == digits || (p.input[digits] == '0' && (p.pos-digits > 1 || digits > start)) {
+		p.pos = start
+		return nil, p.error("integer")
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n > maxJSONInt || n < -maxJSONInt {
+		p.pos = start
+		return nil, p.error("integer in I-JSON range")
+	}
+
+	i := int(n)
+	return &i, nil
+}
+
+// parseString parses single or double quoted string literal with JSON escapes.
+func (p *jsonPathParser) parseString() (string, error) {
+	quote := p.input[p.pos]
+	p.pos++
+
+	var b strings.Builder
+
+	for {
+		if p.pos >= len(p.input) {
+			return "", p.error("closing quote")
+		}
+
+		c := p.input[p.pos]
+
+		switch {
+		case c == quote:
+			p.pos++
+			return b.String(), nil
+
+		case c < 0x20:
+			return "", p.error("escaped control character")
+
+		case c == '\\':
+			p.pos++
+
+			switch e := p.peek(); e {
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '/', '\\':
+				b.WriteByte(e)
+			case quote:
+				b.WriteByte(quote)
+			case 'u':
+				r, err := p.parseUnicodeEscape()
+				if err != nil {
+					return "", err
+				}
+				b.WriteRune(r)
+				continue
+			default:
+				return "", p.error("escape sequence")
+			}
+			p.pos++
+
+		default:
+			r, n := utf8.DecodeRuneInString(p.input[p.pos:])
+			b.WriteRune(r)
+			p.pos += n
+		}
+	}
+}
+
+// parseUnicodeEscape parses `uXXXX` escape including surrogate pairs.
+func (p *jsonPathParser) parseUnicodeEscape() (rune, error) {
+	hex := func() (rune, error) {
+		if p.pos+5 > len(p.input) {
+			return 0, p.error("4 hex digits")
+		}
+		n, err := strconv.ParseUint(p.input[p.pos+1:p.pos+5], 16, 32)
+		if err != nil {
+			return 0, p.error("4 hex digits")
+		}
+		p.pos += 5
+		return rune(n), nil
+	}
+
+	r, err := hex()
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case r >= 0xDC00 && r <= 0xDFFF:
+		return 0, p.error("high surrogate")
+	case r >= 0xD800 && r <= 0xDBFF:
+		if !p.accept(`\`) || p.peek() != 'u' {
+			return 0, p.error("low surrogate")
+		}
+		low, err := hex()
+		if err != nil {
+			return 0, err
+		}
+		if low < 0xDC00 || low > 0xDFFF {
+			return 0, p.error("low surrogate")
+		}
+		return utf16.DecodeRune(r, low), nil
+	}
+
+	return r, nil
+}
+
+func (p *jsonPathParser) parseOr() (jsonPathLogical, error) {
+	x, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		start := p.pos
+		p.skipSpaces()
+
+		if !p.accept("||") {
+			p.pos = start
+			return x, nil
+		}
+
+		p.skipSpaces()
+
+		y, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		x = &jsonPathOr{x, y}
+	}
+}
+
+func (p *jsonPathParser) parseAnd() (jsonPathLogical, error) {
+	x, err := p.parseBasic()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		start := p.pos
+		p.skipSpaces()
+
+		if !p.accept("&&") {
+			p.pos = start
+			return x, nil
+		}
+
+		p.skipSpaces()
+
+		y, err := p.parseBasic()
+		if err != nil {
+			return nil, err
+		}
+		x = &jsonPathAnd{x, y}
+	}
+}
+
+// parseBasic parses parenthesized, comparison or test expression.
+func (p *jsonPathParser) parseBasic() (jsonPathLogical, error) {
+	if p.accept("!") {
+		p.skipSpaces()
+
+		var (
+			x   jsonPathLogical
+			err error
+		)
+
+		if p.peek() == '(' {
+			x, err = p.parseParen()
+		} else {
+			x, err = p.parseTest()
+		}
+
+		if err != nil {
+			return nil, err
+		}
+		return &jsonPathNot{x}, nil
+	}
+
+	if p.peek() == '(' {
+		return p.parseParen()
+	}
+
+	start := p.pos
+
+	x, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	end := p.pos
+	p.skipSpaces()
+
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if !p.accept(op) {
+			continue
+		}
+
+		p.skipSpaces()
+
+		y, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		xv, err := p.comparable(x, start)
+		if err != nil {
+			return nil, err
+		}
+
+		yv, err := p.comparable(y, end)
+		if err != nil {
+			return nil, err
+		}
+
+		return &jsonPathComparison{op, xv, yv}, nil
+	}
+
+	p.pos = end
+	return p.testable(x, start)
+}
+
+func (p *jsonPathParser) parseParen() (jsonPathLogical, error) {
+	p.pos++ // skip (
+	p.skipSpaces()
+
+	x, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+
+	if !p.accept(")") {
+		return nil, p.error("`)`")
+	}
+
+	return x, nil
+}
+
+func (p *jsonPathParser) parseTest() (jsonPathLogical, error) {
+	start := p.pos
+
+	x, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.testable(x, start)
+}
+
+// testable checks that operand could be used as test expression.
+func (p *jsonPathParser) testable(x interface{}, pos int) (jsonPathLogical, error) {
+	switch e := x.(type) {
+	case *jsonPathQuery:
+		return e, nil
+	case *jsonPathFunction:
+		if jsonPathFunctions[e.name].result == jsonPathLogicalType {
+			return e, nil
+		}
+	}
+
+	p.pos = pos
+	return nil, p.error("query or logical function")
+}
+
+// comparable checks that operand could be used in comparison.
+func (p *jsonPathParser) comparable(x interface{}, pos int) (jsonPathValue, error) {
+	switch e := x.(type) {
+	case *jsonPathLiteral:
+		return e, nil
+	case *jsonPathQuery:
+		if e.singular() {
+			return e, nil
+		}
+	case *jsonPathFunction:
+		if jsonPathFunctions[e.name].result == jsonPathValueType {
+			return e, nil
+		}
+	}
+
+	p.pos = pos
+	return nil, p.error("literal, singular query or value function")
+}
+
+// parseOperand parses literal, query or function call.
+func (p *jsonPathParser) parseOperand() (interface{}, error) {
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		p.pos++
+		return p.parseSegments(c == '$')
+
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return &jsonPathLiteral{s}, nil
+
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+	}
+
+	for _, lit := range []struct {
+		token string
+		value interface{}
+	}{{"true", true}, {"false", false}, {"null", nil}} {
+		if p.accept(lit.token) {
+			return &jsonPathLiteral{lit.value}, nil
+		}
+	}
+
+	return p.parseFunction()
+}
+
+var jsonNumberRegex = regexp.MustCompile(`^-?(?:0|[1-9][0-9]*)(?:\.[0-9]+)?(?:[eE][-+]?[0-9]+)?`)
+
+func (p *jsonPathParser) parseNumber() (interface{}, error) {
+	s := jsonNumberRegex.FindString(p.input[p.pos:])
+	if s == "" {
+		return nil, p.error("number")
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, p.error("number")
+	}
+
+	p.pos += len(s)
+	return &jsonPathLiteral{n}, nil
+}
+
+func (p *jsonPathParser) parseFunction() (interface{}, error) {
+	start := p.pos
+
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		if !(c >= 'a' && c <= 'z') && !(p.pos > start && (c == '_' || (c >= '0' && c <= '9'))) {
+			break
+		}
+		p.pos++
+	}
+
+	name := p.input[start:p.pos]
+
+	fn, ok := jsonPathFunctions[name]
+	if !ok {
+		p.pos = start
+		return nil, p.error("literal, query or function")
+	}
+
+	if !p.accept("(") {
+		return nil, p.error("`(`")
+	}
+
+	call := &jsonPathFunction{name: name}
+
+	for i, param := range fn.params {
+		p.skipSpaces()
+
+		if i > 0 {
+			if !p.accept(",") {
+				return nil, p.error("`,`")
+			}
+			p.skipSpaces()
+		}
+
+		argStart := p.pos
+
+		arg, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		switch param {
+		case jsonPathValueType:
+			v, err := p.comparable(arg, argStart)
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, v)
+		case jsonPathNodesType:
+			q, ok := arg.(*jsonPathQuery)
+			if !ok {
+				p.pos = argStart
+				return nil, p.error("query")
+			}
+			call.args = append(call.args, q)
+		}
+	}
+
+	p.skipSpaces()
+
+	if !p.accept(")") {
+		return nil, p.error("`)`")
+	}
+
+	return call, nil
+}
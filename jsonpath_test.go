@@ -0,0 +1,204 @@
+package access
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// testdata/cts.json is the upstream JSONPath Compliance Test Suite, refreshed by go generate.
+//go:generate curl -sSfL -o testdata/cts.json https://raw.githubusercontent.com/jsonpath-standard/jsonpath-compliance-test-suite/main/cts.json
+
+type compliance struct {
+	Tests []struct {
+		Name            string          `json:"name"`
+		Selector        string          `json:"selector"`
+		Document        interface{}     `json:"document"`
+		Result          []interface{}   `json:"result"`
+		Results         [][]interface{} `json:"results"`
+		ResultPaths     []string        `json:"result_paths"`
+		ResultsPaths    [][]string      `json:"results_paths"`
+		InvalidSelector bool            `json:"invalid_selector"`
+	} `json:"tests"`
+}
+
+// ctsSkipped lists cases of the compliance suite which aren't supported, keyed by name with a reason.
+var ctsSkipped = map[string]string{}
+
+// TestJSONPathCompliance runs the vendored JSONPath Compliance Test Suite.
+func TestJSONPathCompliance(t *testing.T) {
+	if _, err := os.Stat("testdata/cts.json"); err != nil {
+		t.Fatalf("Compliance suite is missing, vendor it with go generate: %s", err)
+	}
+
+	runCompliance(t, "testdata/cts.json", ctsSkipped)
+}
+
+// TestJSONPathExamples runs hand-written cases covering RFC 9535 examples.
+func TestJSONPathExamples(t *testing.T) {
+	runCompliance(t, "testdata/rfc9535.json", nil)
+}
+
+func runCompliance(t *testing.T, file string, skipped map[string]string) {
+	data, err := os.ReadFile(file)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var suite compliance
+	if !assert.NoError(t, json.Unmarshal(data, &suite)) {
+		return
+	}
+
+	names := map[string]bool{}
+
+	for _, c := range suite.Tests {
+		names[c.Name] = true
+
+		if _, ok := skipped[c.Name]; ok {
+			continue
+		}
+
+		q, err := ParseJSONPath(c.Selector)
+
+		if c.InvalidSelector {
+			assert.Error(t, err, c.Name)
+			continue
+		}
+
+		if !assert.NoError(t, err, c.Name) {
+			continue
+		}
+
+		matches := q.Select(c.Document)
+
+		values := make([]interface{}, len(matches))
+		paths := make([]string, len(matches))
+		for i, m := range matches {
+			values[i] = m.Value
+			paths[i] = m.Path.NormalizedPath()
+		}
+
+		expected, expectedPaths := c.Results, c.ResultsPaths
+		if c.Result != nil {
+			expected, expectedPaths = [][]interface{}{c.Result}, [][]string{c.ResultPaths}
+		}
+
+		// results lists every allowed order of nodes, result paths follow the matching one
+		found := -1
+		for i, e := range expected {
+			if reflect.DeepEqual(e, values) {
+				found = i
+				break
+			}
+		}
+
+		if !assert.True(t, found >= 0, "%s: %s got %v, expected %v", c.Name, c.Selector, values, expected) {
+			continue
+		}
+
+		if found < len(expectedPaths) && expectedPaths[found] != nil {
+			assert.Equal(t, expectedPaths[found], paths, c.Name)
+		}
+	}
+
+	for name := range skipped {
+		assert.True(t, names[name], "skipped case `%s` is not in %s", name, file)
+	}
+}
+
+type jsonPathBook struct {
+	Title  string
+	Author string
+	Price  float64
+	Isbn   *string
+}
+
+type jsonPathStore struct {
+	Book    []jsonPathBook
+	Bicycle map[string]interface{}
+}
+
+func TestJSONPathGoValues(t *testing.T) {
+	assert := assert.New(t)
+
+	isbn := "0-553-21311-3"
+	store := &jsonPathStore{
+		Book: []jsonPathBook{
+			{"Sayings of the Century", "Nigel Rees", 8.95, nil},
+			{"Moby Dick", "Herman Melville", 8.99, &isbn},
+			{"The Lord of the Rings", "J. R. R. Tolkien", 22.99, nil},
+		},
+		Bicycle: map[string]interface{}{"color": "red", "price": 399},
+	}
+
+	q, err := ParseJSONPath("$.book[?@.price < 10 && @.isbn != null].title")
+	assert.NoError(err)
+	assert.Equal([]Match{{Path{"book", 1, "title"}, "Moby Dick"}}, q.Select(store))
+
+	q, err = ParseJSONPath("$..price")
+	assert.NoError(err)
+	matches := q.Select(store)
+	if assert.Len(matches, 4) {
+		assert.Equal(8.95, matches[0].Value)
+		assert.Equal(399, matches[3].Value)
+	}
+
+	//concrete paths could be used to write values
+	q, err = ParseJSONPath("$.book[-1:]")
+	assert.NoError(err)
+	for _, m := range q.Select(store) {
+		assert.NoError(m.Path.Write(store, jsonPathBook{Title: "The Hobbit"}))
+	}
+	assert.Equal("The Hobbit", store.Book[2].Title)
+
+	//readers
+	q, err = ParseJSONPath("$.fields.firstname")
+	assert.NoError(err)
+	assert.Equal([]Match{{Path{"fields", "firstname"}, "foo"}},
+		q.Select(map[string]interface{}{"fields": Fields{map[string]interface{}{"firstname": "foo"}}}))
+
+	_, err = ParseJSONPath("$.book[?@.price <]")
+	if assert.IsType(&ParseError{}, err) {
+		assert.Equal(17, err.(*ParseError).Offset)
+	}
+
+	assert.Equal(`$['a'][0]['b\'c']`, Path{"a", 0, "b'c"}.NormalizedPath())
+}
+
+func TestJSONPathAccessor(t *testing.T) {
+	assert := assert.New(t)
+
+	type item struct {
+		Name string `yaml:"title"`
+	}
+
+	doc := map[string]interface{}{
+		"items": []item{{"foo"}, {"bar"}},
+		"tree":  map[string]interface{}{"a": map[string]interface{}{"b": map[string]interface{}{"c": map[string]interface{}{"d": map[string]interface{}{"name": "deep"}}}}},
+	}
+
+	a := NewAccessor(WithTagNames("yaml"), WithMaxDepth(3))
+
+	q, err := a.ParseJSONPath("$.items[?@.title == 'bar'].title")
+	assert.NoError(err)
+	assert.Equal([]Match{{Path{"items", 1, "title"}, "bar"}}, q.Select(doc))
+
+	q, err = ParseJSONPath("$.items[?@.title == 'bar'].title")
+	assert.NoError(err)
+	assert.Empty(q.Select(doc))
+
+	//descendants deeper than max depth aren't listed
+	q, err = ParseJSONPath("$..name")
+	assert.NoError(err)
+	assert.Len(q.Select(doc["tree"]), 1)
+
+	q, err = a.ParseJSONPath("$..name")
+	assert.NoError(err)
+	assert.Empty(q.Select(doc["tree"]))
+
+	_, err = a.ParseJSONPath("$.a.b.c.d")
+	assert.ErrorIs(err, ErrMaxDepth)
+}
@@ -0,0 +1,57 @@
+package access
+
+import (
+	"errors"
+	"reflect"
+)
+
+func Lookup(s interface{}, v interface{}) (interface{}, bool, error) {
+	return defaultAccessor().Lookup(s, v)
+}
+
+func Exists(s interface{}, v interface{}) bool {
+	return defaultAccessor().Exists(s, v)
+}
+
+// Lookup reads value at path reporting whether it was found, so that present nil values
+// are distinguished from missing ones. Missing map keys, indices out of range and nil containers
+// on the way are reported as not found, while other failures are returned as errors.
+func (path Path) Lookup(v interface{}) (interface{}, bool, error) {
+	return defaultAccessor().lookupPath(path, v)
+}
+
+func (a *Accessor) lookupPath(path Path, v interface{}) (interface{}, bool, error) {
+
+	if err := a.checkDepth(path); err != nil {
+		return nil, false, err
+	}
+
+	if err := path.checkSingle(); err != nil {
+		return nil, false, err
+	}
+
+	re, err := path.read(a, reflect.ValueOf(v))
+
+	if err != nil {
+		if isNotFound(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	if !re.IsValid() {
+		return nil, true, nil
+	}
+
+	return re.Interface(), true, nil
+}
+
+// Exists checks whether value at path is present, even if it is nil.
+func (path Path) Exists(v interface{}) bool {
+	_, found, err := path.Lookup(v)
+	return found && err == nil
+}
+
+func isNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound) || errors.Is(err, ErrIndexOutOfRange)
+}
@@ -0,0 +1,71 @@
+package access
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type lookupNode struct {
+	Name  *string
+	Next  *lookupNode
+	Items []interface{}
+	Attrs map[string]interface{}
+}
+
+func TestLookup(t *testing.T) {
+	assert := assert.New(t)
+
+	name := "foo"
+	node := &lookupNode{
+		Name:  &name,
+		Next:  &lookupNode{},
+		Items: []interface{}{nil, 1},
+		Attrs: map[string]interface{}{"nil": nil, "one": 1, "list": []int{1}},
+	}
+
+	cases := []struct {
+		path  string
+		value interface{}
+		found bool
+	}{
+		{"name", &name, true},
+		{"next.name", (*string)(nil), true},
+		{"next.next", (*lookupNode)(nil), true},
+		{"next.next.name", nil, false},
+		{"next.next.items[0]", nil, false},
+		{"next.items", []interface{}(nil), true},
+		{"next.items[0]", nil, false},
+		{"next.attrs.key", nil, false},
+		{"items[0]", nil, true},
+		{"items[-1]", 1, true},
+		{"items[2]", nil, false},
+		{"items[-3]", nil, false},
+		{"attrs.nil", nil, true},
+		{"attrs.nil.key", nil, false},
+		{"attrs.one", 1, true},
+		{"attrs.two", nil, false},
+		{"attrs.list[0]", 1, true},
+		{"attrs.list[1]", nil, false},
+	}
+
+	for _, c := range cases {
+		val, found, err := Lookup(c.path, node)
+		assert.NoError(err, c.path)
+		assert.Equal(c.found, found, c.path)
+		assert.Equal(c.value, val, c.path)
+		assert.Equal(c.found, Exists(c.path, node), c.path)
+	}
+
+	_, found, err := Lookup("unknown", node)
+	assert.False(found)
+	assert.NoError(err)
+	assert.False(Exists("unknown", node))
+
+	_, found, err = Lookup("attrs.one.key", node)
+	assert.False(found)
+	assert.Error(err)
+
+	_, found, err = Lookup("items[*]", node)
+	assert.False(found)
+	assert.Error(err)
+}
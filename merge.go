@@ -0,0 +1,193 @@
+package access
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// MergePatch applies RFC 7386 JSON Merge Patch document to value pointed by target.
+// Objects are merged recursively through the same field resolution as Write, null members
+// delete map keys or zero other values, and remaining values replace the target ones,
+// being decoded into the type of value they replace when it is known.
+func MergePatch(target interface{}, patch []byte) error {
+
+	if rv := reflect.ValueOf(target); rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return Error{kindError{fmt.Errorf("Non pointer value"), ErrNotAddressable}, []interface{}{}}
+	}
+
+	return mergePatch(defaultAccessor(), target, Path{}, json.RawMessage(patch))
+}
+
+func mergePatch(a *Accessor, target interface{}, path Path, patch json.RawMessage) error {
+
+	if !isJSONObject(patch) {
+		return mergeValue(a, target, path, patch)
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &members); err != nil {
+		return Error{err, path}
+	}
+
+	if err := mergeObject(a, target, path); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(members))
+	for k := range members {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		member := append(path[:len(path):len(path)], k)
+
+		var err error
+		if bytes.Equal(bytes.TrimSpace(members[k]), []byte("null")) {
+			err = mergeDelete(a, target, member)
+		} else {
+			err = mergePatch(a, target, member, members[k])
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mergeObject prepares value at path to receive object members, creating missing values,
+// allocating nil maps and pointers and replacing non object values with empty map.
+func mergeObject(a *Accessor, target interface{}, path Path) error {
+
+	v, err := path.read(a, reflect.ValueOf(target))
+	if err != nil {
+		return a.writePath(path, target, emptyObject(mergeType(a, target, path)))
+	}
+
+	for v.IsValid() {
+
+		if implements(v.Type(), fieldReaderInterface) {
+			return nil
+		}
+
+		switch v.Kind() {
+		case reflect.Interface, reflect.Ptr:
+			if v.IsNil() {
+				return a.writePath(path, target, emptyObject(mergeType(a, target, path)))
+			}
+			v = v.Elem()
+			continue
+
+		case reflect.Map:
+			if v.IsNil() {
+				return a.writePath(path, target, emptyObject(v.Type()))
+			}
+			return nil
+
+		case reflect.Struct:
+			return nil
+		}
+
+		break
+	}
+
+	return a.writePath(path, target, emptyObject(nil))
+}
+
+// emptyObject returns empty value of map, struct or pointer type t, or empty generic map otherwise.
+func emptyObject(t reflect.Type) interface{} {
+
+	if t == nil {
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Map:
+		return reflect.MakeMap(t).Interface()
+	case reflect.Struct:
+		return reflect.Zero(t).Interface()
+	case reflect.Ptr:
+		p := reflect.New(t.Elem())
+		if t.Elem().Kind() == reflect.Map {
+			p.Elem().Set(reflect.MakeMap(t.Elem()))
+		}
+		return p.Interface()
+	}
+
+	return map[string]interface{}{}
+}
+
+// mergeValue writes JSON value at path, decoding it into the type of replaced value.
+func mergeValue(a *Accessor, target interface{}, path Path, raw json.RawMessage) error {
+
+	var val interface{}
+
+	if t := mergeType(a, target, path); t != nil {
+		nv := reflect.New(t)
+		if err := json.Unmarshal(raw, nv.Interface()); err != nil {
+			return Error{err, path}
+		}
+		val = nv.Elem().Interface()
+	} else if err := json.Unmarshal(raw, &val); err != nil {
+		return Error{err, path}
+	}
+
+	if len(path) == 0 {
+		rv := reflect.ValueOf(target).Elem()
+		if val == nil {
+			rv.Set(reflect.Zero(rv.Type()))
+		} else {
+			rv.Set(reflect.ValueOf(val))
+		}
+		return nil
+	}
+
+	return a.writePath(path, target, val)
+}
+
+// mergeDelete deletes value at path, ignoring missing ones.
+func mergeDelete(a *Accessor, target interface{}, path Path) error {
+
+	if _, found, err := a.lookupPath(path, target); err != nil || !found {
+		return err
+	}
+
+	return a.deletePath(path, target)
+}
+
+// mergeType returns concrete type of value at path or element type of map, slice or array when it's missing,
+// nil is returned for interface values and unknown types.
+func mergeType(a *Accessor, target interface{}, path Path) reflect.Type {
+
+	var t reflect.Type
+
+	if len(path) == 0 {
+		t = reflect.TypeOf(target).Elem()
+	} else if v, err := path.read(a, reflect.ValueOf(target)); err == nil && v.IsValid() {
+		t = v.Type()
+	} else if parent, err := path[:len(path)-1].read(a, reflect.ValueOf(target)); err == nil && parent.IsValid() {
+		for (parent.Kind() == reflect.Ptr || parent.Kind() == reflect.Interface) && !parent.IsNil() {
+			parent = parent.Elem()
+		}
+		switch parent.Kind() {
+		case reflect.Map, reflect.Slice, reflect.Array:
+			t = parent.Type().Elem()
+		}
+	}
+
+	if t == nil || t.Kind() == reflect.Interface {
+		return nil
+	}
+
+	return t
+}
+
+func isJSONObject(raw json.RawMessage) bool {
+	raw = bytes.TrimSpace(raw)
+	return len(raw) > 0 && raw[0] == '{'
+}
@@ -0,0 +1,111 @@
+package access
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMergePatch(t *testing.T) {
+
+	//examples from RFC 7386 appendix A
+	cases := [][3]string{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		{`{"a":"b"}`, `{"a":{"c":"d"}}`, `{"a":{"c":"d"}}`},
+	}
+
+	for _, c := range cases {
+		var doc, expected interface{}
+
+		assert.NoError(t, json.Unmarshal([]byte(c[0]), &doc))
+		assert.NoError(t, json.Unmarshal([]byte(c[2]), &expected))
+
+		assert.NoError(t, MergePatch(&doc, []byte(c[1])), c[1])
+		assert.Equal(t, expected, doc, c[1])
+	}
+}
+
+type mergeAddress struct {
+	City string
+	Zip  *string
+}
+
+type mergeUser struct {
+	FirstName string
+	Age       int32
+	Address   *mergeAddress
+	Tags      []string
+	Scores    map[string]int
+	Extra     interface{}
+	nickname  string
+}
+
+func (u *mergeUser) Nickname() string {
+	return u.nickname
+}
+
+func (u *mergeUser) SetNickname(nickname string) {
+	u.nickname = nickname
+}
+
+func TestMergePatchStruct(t *testing.T) {
+	assert := assert.New(t)
+
+	zip := "00-001"
+	user := &mergeUser{
+		FirstName: "foo",
+		Age:       20,
+		Address:   &mergeAddress{"Warsaw", &zip},
+		Tags:      []string{"a"},
+		Extra:     map[string]interface{}{"x": 1.0},
+	}
+
+	assert.NoError(MergePatch(user, []byte(`{
+		"first_name": "bar",
+		"age": 21,
+		"address": {"city": "Cracow", "zip": null},
+		"tags": ["b", "c"],
+		"scores": {"math": 5},
+		"extra": {"x": null, "y": true},
+		"nickname": "baz"
+	}`)))
+
+	assert.Equal(&mergeUser{
+		FirstName: "bar",
+		Age:       21,
+		Address:   &mergeAddress{"Cracow", nil},
+		Tags:      []string{"b", "c"},
+		Scores:    map[string]int{"math": 5},
+		Extra:     map[string]interface{}{"y": true},
+		nickname:  "baz",
+	}, user)
+
+	assert.NoError(MergePatch(user, []byte(`{"address": null, "age": null, "scores": {"math": null, "art": 4}}`)))
+	assert.Nil(user.Address)
+	assert.Equal(int32(0), user.Age)
+	assert.Equal(map[string]int{"art": 4}, user.Scores)
+
+	assert.NoError(MergePatch(user, []byte(`{"address": {"city": "Gdansk"}}`)))
+	assert.Equal(&mergeAddress{City: "Gdansk"}, user.Address)
+
+	assert.Error(MergePatch(user, []byte(`{"age": "old"}`)))
+	assert.Error(MergePatch(user, []byte(`{"unknown": 1}`)))
+	assert.NoError(MergePatch(user, []byte(`{"unknown": null}`)))
+	assert.NoError(MergePatch(user, []byte(`{"scores": {"missing": null}}`)))
+	assert.Error(MergePatch(user, []byte(`{"age":`)))
+	assert.Error(MergePatch(*user, []byte(`{}`)))
+}
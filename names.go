@@ -0,0 +1,145 @@
+package access
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NameMatcher decides whether name used in path refers to struct field or method with given Go name.
+type NameMatcher interface {
+	Match(name, goName string) bool
+}
+
+// NameMatcherFunc is a function implementing NameMatcher.
+type NameMatcherFunc func(name, goName string) bool
+
+func (f NameMatcherFunc) Match(name, goName string) bool {
+	return f(name, goName)
+}
+
+var (
+	// ExactMatcher matches names equal to Go names.
+	ExactMatcher NameMatcher = NameMatcherFunc(func(name, goName string) bool {
+		return name == goName
+	})
+
+	// CaseInsensitiveMatcher matches names equal to Go names ignoring case.
+	CaseInsensitiveMatcher NameMatcher = NameMatcherFunc(func(name, goName string) bool {
+		return strings.EqualFold(name, goName)
+	})
+
+	// NormalizedMatcher matches snake_case, kebab-case and camelCase names to Go names word by word
+	// ignoring case, so `user_id`, `user-id` and `userId` match both `UserID` and `UserId`.
+	NormalizedMatcher NameMatcher = NameMatcherFunc(func(name, goName string) bool {
+		nw, gw := words(name), words(goName)
+
+		if len(nw) != len(gw) {
+			return false
+		}
+
+		for i := range nw {
+			if !strings.EqualFold(nw[i], gw[i]) {
+				return false
+			}
+		}
+
+		return true
+	})
+)
+
+// commonInitialisms are words written in upper case in Go names.
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true,
+	"GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"LHS": true, "QPS": true, "RAM": true, "RHS": true, "RPC": true, "SLA": true, "SMTP": true,
+	"SQL": true, "SSH": true, "TCP": true, "TLS": true, "TTL": true, "UDP": true, "UI": true,
+	"UID": true, "UUID": true, "URI": true, "URL": true, "UTF8": true, "VM": true, "XML": true,
+	"XMPP": true, "XSRF": true, "XSS": true,
+}
+
+// camelcased converts snake_case, kebab-case or camelCase name into Go name, so `user_id` becomes `UserID`.
+func camelcased(s string) string {
+
+	var b strings.Builder
+
+	for _, word := range words(s) {
+		if upper := strings.ToUpper(word); commonInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+
+		if upper := strings.ToUpper(word); strings.HasSuffix(upper, "S") && commonInitialisms[upper[:len(upper)-1]] {
+			b.WriteString(upper[:len(upper)-1] + "s")
+			continue
+		}
+
+		r := []rune(word)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+
+	return b.String()
+}
+
+// words splits name on underscores, dashes, spaces and case changes, keeping upper case runs like `HTTP` together.
+func words(s string) []string {
+
+	var (
+		result []string
+		word   []rune
+	)
+
+	r := []rune(s)
+
+	for i, c := range r {
+
+		if c == '_' || c == '-' || c == ' ' {
+			if len(word) > 0 {
+				result = append(result, string(word))
+			}
+			word = word[:0:0]
+			continue
+		}
+
+		if len(word) > 0 && unicode.IsUpper(c) {
+			prev := word[len(word)-1]
+			next := i+1 < len(r) && unicode.IsLower(r[i+1])
+
+			// plural initialisms like `IDs` are single words
+			if next && commonInitialisms[string(word)+string(c)] && r[i+1] == 's' && (i+2 == len(r) || !unicode.IsLower(r[i+2])) {
+				next = false
+			}
+
+			if !unicode.IsUpper(prev) || next {
+				result = append(result, string(word))
+				word = word[:0:0]
+			}
+		}
+
+		word = append(word, c)
+	}
+
+	if len(word) > 0 {
+		result = append(result, string(word))
+	}
+
+	return result
+}
+
+// findStructMethod returns index of method in mt named by prefix and Go name matching name, or -1.
+func findStructMethod(a *Accessor, mt *methodTable, name string, prefix string) int {
+
+	m := a.matcher
+
+	if i, ok := mt.indices[prefix+camelcased(name)]; ok && m.Match(name, camelcased(name)) {
+		return i
+	}
+
+	for i, mn := range mt.names {
+		if strings.HasPrefix(mn, prefix) && m.Match(name, mn[len(prefix):]) {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,111 @@
+package access
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCamelcased(t *testing.T) {
+	cases := map[string]string{
+		"name":        "Name",
+		"first_name":  "FirstName",
+		"user_id":     "UserID",
+		"user-id":     "UserID",
+		"userId":      "UserID",
+		"UserID":      "UserID",
+		"id":          "ID",
+		"http_server": "HTTPServer",
+		"HTTPServer":  "HTTPServer",
+		"user_ids":    "UserIDs",
+		"userIDs":     "UserIDs",
+		"url_list":    "URLList",
+		"GlossDiv":    "GlossDiv",
+		"gloss_div":   "GlossDiv",
+		"isbn":        "Isbn",
+		"a__b":        "AB",
+	}
+
+	for name, expected := range cases {
+		assert.Equal(t, expected, camelcased(name), name)
+	}
+}
+
+func TestNameMatchers(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(ExactMatcher.Match("UserID", "UserID"))
+	assert.False(ExactMatcher.Match("userID", "UserID"))
+
+	assert.True(CaseInsensitiveMatcher.Match("userid", "UserID"))
+	assert.False(CaseInsensitiveMatcher.Match("user_id", "UserID"))
+
+	for _, name := range []string{"user_id", "user-id", "userId", "UserID", "USER_ID", "user id"} {
+		assert.True(NormalizedMatcher.Match(name, "UserID"), name)
+		assert.True(NormalizedMatcher.Match(name, "UserId"), name)
+	}
+
+	assert.False(NormalizedMatcher.Match("userid", "UserID"))
+	assert.False(NormalizedMatcher.Match("first_name", "Firstname"))
+	assert.True(NormalizedMatcher.Match("ids", "IDs"))
+	assert.True(NormalizedMatcher.Match("http_server", "HTTPServer"))
+}
+
+type namedUser struct {
+	UserID     int
+	HTTPServer string
+	IDs        []int
+	Legacy_id  string
+	apiKey     string
+}
+
+func (u *namedUser) APIKey() string {
+	return u.apiKey
+}
+
+func (u *namedUser) SetAPIKey(key string) {
+	u.apiKey = key
+}
+
+func TestNameMatching(t *testing.T) {
+	assert := assert.New(t)
+
+	user := &namedUser{1, "localhost", []int{1, 2}, "x", "key"}
+
+	for _, name := range []string{"user_id", `["user-id"]`, "userId", "UserID"} {
+		assert.Equal(1, MustRead(name, user), name)
+	}
+
+	assert.Equal("localhost", MustRead("http_server", user))
+	assert.Equal(2, MustRead("ids[1]", user))
+	assert.Equal("key", MustRead("api_key", user))
+	assert.Equal("x", MustRead("Legacy_id", user))
+	assert.Nil(MustRead("api_key_id", user))
+
+	assert.NoError(Write("user_id", user, 2))
+	assert.NoError(Write(`["api-key"]`, user, "secret"))
+	assert.Equal(2, user.UserID)
+	assert.Equal("secret", user.apiKey)
+
+	SetNameMatcher(ExactMatcher)
+	defer SetNameMatcher(NormalizedMatcher)
+
+	assert.Nil(MustRead("user_id", user))
+	assert.Nil(MustRead("api_key", user))
+	assert.Equal(2, MustRead("UserID", user))
+	assert.Equal("secret", MustRead("APIKey", user))
+	assert.Error(Write("user_id", user, 3))
+
+	SetNameMatcher(CaseInsensitiveMatcher)
+
+	assert.Equal(2, MustRead("userid", user))
+	assert.Equal("secret", MustRead("apikey", user))
+	assert.Nil(MustRead("user_id", user))
+	assert.Nil(MustRead("apikey2", user))
+
+	SetNameMatcher(NameMatcherFunc(func(name, goName string) bool {
+		return "x"+goName == name
+	}))
+
+	assert.Equal(2, MustRead("xUserID", user))
+	assert.Nil(MustRead("UserID", user))
+}
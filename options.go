@@ -0,0 +1,209 @@
+package access
+
+import (
+	"fmt"
+	"reflect"
+	"sync/atomic"
+)
+
+// Accessor reads and writes paths according to its options,
+// package functions and Path methods use the default instance.
+// Its methods take string selectors or Paths, selectors which can't be parsed fail with *ParseError.
+type Accessor struct {
+	matcher   NameMatcher
+	tags      []string
+	converter *Converter
+	allocate  bool
+	methods   bool
+	maxDepth  int
+	cache     *typeCache
+}
+
+// Option configures an Accessor.
+type Option func(*Accessor)
+
+// WithNameMatcher sets matcher of struct fields and methods, NormalizedMatcher by default.
+func WithNameMatcher(m NameMatcher) Option {
+	return func(a *Accessor) {
+		a.matcher = m
+	}
+}
+
+// WithTagNames sets struct tags which name fields, checked in order, none by default.
+// Fields are matched by the first tag present on them with a name, falling back to their Go name,
+// while fields tagged with `-` are hidden. No names disable tags.
+func WithTagNames(names ...string) Option {
+	return func(a *Accessor) {
+		a.tags = names
+	}
+}
+
+// WithConverter enables converting written values which are not assignable to the target
+// and values read by Get, GetOr and MustGet which are not of requested type. Nil disables conversion.
+func WithConverter(c *Converter) Option {
+	return func(a *Accessor) {
+		a.converter = c
+	}
+}
+
+// WithAllocation enables or disables allocating nil pointers, maps and interfaces while writing, enabled by default.
+func WithAllocation(enabled bool) Option {
+	return func(a *Accessor) {
+		a.allocate = enabled
+	}
+}
+
+// WithMethods enables or disables calling getter and setter methods of structs, enabled by default.
+func WithMethods(enabled bool) Option {
+	return func(a *Accessor) {
+		a.methods = enabled
+	}
+}
+
+// WithMaxDepth limits number of path elements, failing with error matching ErrMaxDepth,
+// and depth of values listed by recursive descent. 0 means no limit.
+func WithMaxDepth(depth int) Option {
+	return func(a *Accessor) {
+		a.maxDepth = depth
+	}
+}
+
+// NewAccessor creates an Accessor with default options changed by given ones.
+func NewAccessor(options ...Option) *Accessor {
+	a := &Accessor{
+		matcher:  NormalizedMatcher,
+		allocate: true,
+		methods:  true,
+	}
+
+	for _, o := range options {
+		o(a)
+	}
+
+	a.cache = &typeCache{}
+
+	return a
+}
+
+var defaultInstance atomic.Pointer[Accessor]
+
+func init() {
+	defaultInstance.Store(NewAccessor())
+}
+
+func defaultAccessor() *Accessor {
+	return defaultInstance.Load()
+}
+
+// DefaultAccessor returns the default instance used by package functions and Path methods.
+func DefaultAccessor() *Accessor {
+	return defaultAccessor()
+}
+
+// Configure changes options of the default instance used by package functions and Path methods.
+func Configure(options ...Option) {
+	for {
+		old := defaultInstance.Load()
+
+		a := *old
+		for _, o := range options {
+			o(&a)
+		}
+		a.cache = &typeCache{}
+
+		if defaultInstance.CompareAndSwap(old, &a) {
+			return
+		}
+	}
+}
+
+// SetNameMatcher sets matcher of struct fields and methods of the default instance.
+func SetNameMatcher(m NameMatcher) {
+	Configure(WithNameMatcher(m))
+}
+
+// SetTagNames sets struct tags which name fields of the default instance.
+func SetTagNames(names ...string) {
+	Configure(WithTagNames(names...))
+}
+
+// SetConverter sets converter of the default instance.
+func SetConverter(c *Converter) {
+	Configure(WithConverter(c))
+}
+
+// Read reads value at selector s.
+func (a *Accessor) Read(s interface{}, v interface{}) (interface{}, error) {
+	path, err := selectorPath(s)
+	if err != nil {
+		return nil, err
+	}
+	return a.readPath(path, v)
+}
+
+// MustRead reads value at selector s, returning the default value dv when it fails.
+func (a *Accessor) MustRead(s interface{}, v interface{}, dv ...interface{}) interface{} {
+	path, err := selectorPath(s)
+	if err != nil {
+		if len(dv) == 1 {
+			return dv[0]
+		}
+		return nil
+	}
+	return a.mustReadPath(path, v, dv...)
+}
+
+// Write writes value at selector s.
+func (a *Accessor) Write(s interface{}, v interface{}, val interface{}) error {
+	path, err := selectorPath(s)
+	if err != nil {
+		return err
+	}
+	return a.writePath(path, v, val)
+}
+
+// Delete removes value at selector s.
+func (a *Accessor) Delete(s interface{}, v interface{}) error {
+	path, err := selectorPath(s)
+	if err != nil {
+		return err
+	}
+	return a.deletePath(path, v)
+}
+
+// ReadAll reads every location matched by selector s.
+func (a *Accessor) ReadAll(s interface{}, v interface{}) ([]Match, error) {
+	path, err := selectorPath(s)
+	if err != nil {
+		return nil, err
+	}
+	return a.readAllPath(path, v)
+}
+
+// Lookup reads value at selector s reporting whether it was found.
+func (a *Accessor) Lookup(s interface{}, v interface{}) (interface{}, bool, error) {
+	path, err := selectorPath(s)
+	if err != nil {
+		return nil, false, err
+	}
+	return a.lookupPath(path, v)
+}
+
+// Exists reports whether value at selector s exists.
+func (a *Accessor) Exists(s interface{}, v interface{}) bool {
+	_, found, err := a.Lookup(s, v)
+	return found && err == nil
+}
+
+// checkDepth fails when path is longer than allowed.
+func (a *Accessor) checkDepth(path Path) error {
+	if a.maxDepth > 0 && len(path) > a.maxDepth {
+		return Error{kindError{fmt.Errorf("Path of %d elements exceeds max depth %d", len(path), a.maxDepth), ErrMaxDepth}, []interface{}{}}
+	}
+	return nil
+}
+
+// allocationError reports nil value of type t which can't be allocated.
+func allocationError(t reflect.Type) error {
+	return kindError{fmt.Errorf("Nil %s can't be allocated", t), ErrNotFound}
+}
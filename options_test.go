@@ -0,0 +1,133 @@
+package access
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type optionsInner struct {
+	Value int
+}
+
+type optionsOuter struct {
+	UserID int `yaml:"uid"`
+	Inner  *optionsInner
+	Attrs  map[string]interface{}
+	Any    interface{}
+	secret string
+}
+
+func (o *optionsOuter) Secret() string {
+	return o.secret
+}
+
+func TestAccessorDefaults(t *testing.T) {
+	assert := assert.New(t)
+
+	a := NewAccessor()
+	o := &optionsOuter{secret: "s"}
+
+	assert.NoError(a.Write("inner.value", o, 5))
+	assert.Equal(5, o.Inner.Value)
+
+	assert.NoError(a.Write("attrs.key", o, "v"))
+	assert.Equal("v", a.MustRead("attrs.key", o))
+
+	val, err := a.Read("secret", o)
+	assert.NoError(err)
+	assert.Equal("s", val)
+
+	val, found, err := a.Lookup("any.key", o)
+	assert.NoError(err)
+	assert.False(found)
+	assert.Nil(val)
+	assert.True(a.Exists("user_id", o))
+
+	matches, err := a.ReadAll("inner.*", o)
+	assert.NoError(err)
+	assert.Equal([]Match{{Path{"inner", "Value"}, 5}}, matches)
+
+	assert.NoError(a.Delete("inner.value", o))
+	assert.Equal(0, o.Inner.Value)
+
+	//selectors which can't be parsed fail instead of panicking
+	var perr *ParseError
+	_, err = a.Read("inner.", o)
+	assert.True(errors.As(err, &perr))
+	_, err = a.ReadAll("inner[*", o)
+	assert.True(errors.As(err, &perr))
+	_, _, err = a.Lookup("inner.", o)
+	assert.True(errors.As(err, &perr))
+	assert.True(errors.As(a.Write("inner.", o, 1), &perr))
+	assert.True(errors.As(a.Delete("inner.", o), &perr))
+	assert.False(a.Exists("inner.", o))
+	assert.Equal(1, a.MustRead("inner.", o, 1))
+
+	_, err = ReadAll("inner[*", o)
+	assert.True(errors.As(err, &perr))
+}
+
+func TestAccessorOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	o := &optionsOuter{secret: "s"}
+
+	a := NewAccessor(WithAllocation(false))
+
+	for _, path := range []string{"inner.value", "attrs.key", "any.key", "any[0]", "any[-]", "any[0:1]"} {
+		err := a.Write(path, o, 1)
+		assert.True(errors.Is(err, ErrNotFound), path)
+	}
+	assert.Equal(&optionsOuter{secret: "s"}, o)
+
+	o.Inner = &optionsInner{}
+	assert.NoError(a.Write("inner.value", o, 1))
+	assert.Equal(1, o.Inner.Value)
+
+	a = NewAccessor(WithMethods(false))
+	_, err := a.Read("secret", o)
+	assert.True(errors.Is(err, ErrNotFound))
+
+	a = NewAccessor(WithNameMatcher(ExactMatcher), WithTagNames("yaml"))
+	assert.True(a.Exists("uid", o))
+	assert.False(a.Exists("user_id", o))
+	assert.False(a.Exists("inner", o))
+
+	a = NewAccessor(WithConverter(NewConverter()))
+	assert.NoError(a.Write("user_id", o, "42"))
+	assert.Equal(42, o.UserID)
+	assert.Error(NewAccessor().Write("user_id", o, "43"))
+
+	a = NewAccessor(WithMaxDepth(2))
+	_, err = a.Read("inner.value", o)
+	assert.NoError(err)
+	_, err = a.Read("inner.value.x", o)
+	assert.True(errors.Is(err, ErrMaxDepth))
+
+	data := map[string]interface{}{"a": map[string]interface{}{"b": map[string]interface{}{"d": map[string]interface{}{"c": 1}}}}
+	matches, err := a.ReadAll("..c", data)
+	assert.NoError(err)
+	assert.Empty(matches)
+
+	matches, err = NewAccessor().ReadAll("..c", data)
+	assert.NoError(err)
+	assert.Equal([]Match{{Path{"a", "b", "d", "c"}, 1}}, matches)
+}
+
+func TestConfigure(t *testing.T) {
+	assert := assert.New(t)
+
+	Configure(WithMethods(false), WithMaxDepth(1))
+	defer Configure(WithMethods(true), WithMaxDepth(0))
+
+	o := &optionsOuter{secret: "s"}
+
+	_, err := Read("secret", o)
+	assert.True(errors.Is(err, ErrNotFound))
+
+	err = Write("inner.value", o, 1)
+	assert.True(errors.Is(err, ErrMaxDepth))
+
+	assert.True(NewAccessor().Exists("secret", o))
+}
@@ -0,0 +1,387 @@
+package access
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Operation is a single RFC 6902 JSON Patch operation. Decoded operations keep their value
+// as json.RawMessage, which is decoded into the type of value it's written over or compared with.
+type Operation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value"`
+	// noValue is true when decoded operation has no value member, which differs from null
+	noValue bool
+}
+
+// UnmarshalJSON decodes operation, keeping its value as json.RawMessage and telling missing value apart from null.
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	var op struct {
+		Op    string          `json:"op"`
+		Path  string          `json:"path"`
+		From  string          `json:"from"`
+		Value json.RawMessage `json:"value"`
+	}
+
+	if err := json.Unmarshal(data, &op); err != nil {
+		return err
+	}
+
+	*o = Operation{Op: op.Op, Path: op.Path, From: op.From, noValue: op.Value == nil}
+
+	if op.Value != nil {
+		o.Value = op.Value
+	}
+
+	return nil
+}
+
+// ApplyPatch applies RFC 6902 JSON Patch operations to value pointed by target.
+// Operations are applied to a deep copy of target, which replaces it only when all of them succeed,
+// so failed patch leaves target untouched and returns Error carrying path of the failing operation.
+//
+// Unexported struct fields are copied shallowly, so values they reference are shared with target.
+// FieldWriter, IndexWriter and PathWriter instances keeping their state there are modified in place
+// and are left modified by operations preceding the failing one.
+func ApplyPatch(target interface{}, patch []Operation) error {
+
+	rv := reflect.ValueOf(target)
+
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return Error{kindError{fmt.Errorf("Non pointer value"), ErrNotAddressable}, []interface{}{}}
+	}
+
+	doc := reflect.New(rv.Elem().Type())
+	doc.Elem().Set(deepCopy(rv.Elem(), map[visitKey]reflect.Value{}))
+
+	for i, op := range patch {
+		if err := applyOperation(doc.Interface(), op); err != nil {
+			if e, ok := err.(Error); ok {
+				err = e.error
+			}
+
+			path, perr := FromPointer(op.Path)
+			if perr != nil {
+				path = Path{}
+			}
+
+			return Error{fmt.Errorf("%s operation #%d failed: %w", op.Op, i, err), path}
+		}
+	}
+
+	rv.Elem().Set(doc.Elem())
+	return nil
+}
+
+func applyOperation(doc interface{}, op Operation) error {
+
+	a := defaultAccessor()
+
+	path, err := pointerPath(a, doc, op.Path)
+	if err != nil {
+		return err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.noValue {
+			return fmt.Errorf("Missing value")
+		}
+	}
+
+	switch op.Op {
+	case "add":
+		val, err := patchValue(a, doc, path, op.Value)
+		if err != nil {
+			return err
+		}
+		return patchAdd(doc, path, val)
+
+	case "remove":
+		return path.Delete(doc)
+
+	case "replace":
+		if _, err := path.Read(doc); err != nil {
+			return err
+		}
+		val, err := patchValue(a, doc, path, op.Value)
+		if err != nil {
+			return err
+		}
+		return path.Write(doc, val)
+
+	case "move", "copy":
+		from, err := pointerPath(a, doc, op.From)
+		if err != nil {
+			return err
+		}
+
+		val, err := from.Read(doc)
+		if err != nil {
+			return err
+		}
+
+		if op.Op == "copy" {
+			if val != nil {
+				val = deepCopy(reflect.ValueOf(val), map[visitKey]reflect.Value{}).Interface()
+			}
+			return patchAdd(doc, path, val)
+		}
+
+		if len(path) > len(from) && reflect.DeepEqual(path[:len(from)], from) {
+			return Error{fmt.Errorf("Can't move value into itself"), path}
+		}
+
+		if err := from.Delete(doc); err != nil {
+			return err
+		}
+		return patchAdd(doc, path, val)
+
+	case "test":
+		val, err := path.Read(doc)
+		if err != nil {
+			return err
+		}
+
+		expected := op.Value
+
+		// JSON values are compared with JSON encoding of the tested one
+		if raw, ok := op.Value.(json.RawMessage); ok {
+			if err := json.Unmarshal(raw, &expected); err != nil {
+				return Error{err, path}
+			}
+			if val, err = patchJSON(val); err != nil {
+				return Error{err, path}
+			}
+		}
+
+		if !patchEqual(val, expected) {
+			return Error{fmt.Errorf("Value %#v is not equal to %#v", val, expected), path}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("Unknown operation `%s`", op.Op)
+}
+
+// patchValue decodes JSON value into the type of value at path like MergePatch does,
+// leaving other values as they are.
+func patchValue(a *Accessor, doc interface{}, path Path, value interface{}) (interface{}, error) {
+
+	raw, ok := value.(json.RawMessage)
+	if !ok {
+		return value, nil
+	}
+
+	var val interface{}
+
+	if t := mergeType(a, doc, path); t != nil {
+		nv := reflect.New(t)
+		if err := json.Unmarshal(raw, nv.Interface()); err != nil {
+			return nil, Error{err, path}
+		}
+		val = nv.Elem().Interface()
+	} else if err := json.Unmarshal(raw, &val); err != nil {
+		return nil, Error{err, path}
+	}
+
+	return val, nil
+}
+
+// patchJSON returns v as decoded from its JSON encoding.
+func patchJSON(v interface{}) (interface{}, error) {
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var val interface{}
+	err = json.Unmarshal(data, &val)
+
+	return val, err
+}
+
+// patchAdd inserts value into arrays and sets it elsewhere, requiring parent to exist.
+func patchAdd(doc interface{}, path Path, value interface{}) error {
+
+	if len(path) == 0 {
+		return path.Write(doc, value)
+	}
+
+	parentPath := path[: len(path)-1 : len(path)-1]
+
+	parent, err := parentPath.Read(doc)
+	if err != nil {
+		return err
+	}
+
+	if i, ok := path[len(path)-1].(int); ok {
+		if l, ok := jsonArray(reflect.ValueOf(parent)); ok {
+			if i > l {
+				return Error{kindError{fmt.Errorf("Index %d out of range %d.", i, l), ErrIndexOutOfRange}, path}
+			}
+
+			// IndexWriter instances can't splice ranges, so elements are shifted one by one
+			if _, ok := indirectRead(reflect.ValueOf(parent), indexReaderInterface).Interface().(IndexReader); ok {
+				return patchInsert(doc, parentPath, i, l, value)
+			}
+			return append(parentPath, Range{Start: &i, End: &i}).Write(doc, []interface{}{value})
+		}
+	}
+
+	return path.Write(doc, value)
+}
+
+// patchInsert inserts value at index i of IndexWriter of length l at parentPath,
+// appending its last element and shifting the following ones.
+func patchInsert(doc interface{}, parentPath Path, i, l int, value interface{}) error {
+
+	for j := l; j > i; j-- {
+		prev, err := append(parentPath, j-1).Read(doc)
+		if err != nil {
+			return err
+		}
+
+		var target interface{} = j
+		if j == l {
+			target = Append{}
+		}
+
+		if err := append(parentPath, target).Write(doc, prev); err != nil {
+			return err
+		}
+	}
+
+	if i == l {
+		return append(parentPath, Append{}).Write(doc, value)
+	}
+
+	return append(parentPath, i).Write(doc, value)
+}
+
+// patchEqual compares values the way JSON does, so numbers of different types are equal.
+func patchEqual(x, y interface{}) bool {
+
+	xv, yv := reflect.ValueOf(filterValue(reflect.ValueOf(x))), reflect.ValueOf(filterValue(reflect.ValueOf(y)))
+
+	if !xv.IsValid() || !yv.IsValid() {
+		return xv.IsValid() == yv.IsValid()
+	}
+
+	switch {
+	case (xv.Kind() == reflect.Slice || xv.Kind() == reflect.Array) && (yv.Kind() == reflect.Slice || yv.Kind() == reflect.Array):
+		if xv.Len() != yv.Len() {
+			return false
+		}
+		for i := 0; i < xv.Len(); i++ {
+			if !patchEqual(xv.Index(i).Interface(), yv.Index(i).Interface()) {
+				return false
+			}
+		}
+		return true
+
+	case xv.Kind() == reflect.Map && yv.Kind() == reflect.Map:
+		if xv.Len() != yv.Len() {
+			return false
+		}
+		for _, k := range xv.MapKeys() {
+			yk := yv.MapIndex(k)
+			if !yk.IsValid() || !patchEqual(xv.MapIndex(k).Interface(), yk.Interface()) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return equal(xv.Interface(), yv.Interface())
+}
+
+// deepCopy copies v together with values referenced by pointers, maps, slices and interfaces.
+func deepCopy(v reflect.Value, copies map[visitKey]reflect.Value) reflect.Value {
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+
+		k := visitKey{v.Type(), v.Pointer(), 0}
+		if c, ok := copies[k]; ok {
+			return c
+		}
+
+		c := reflect.New(v.Type().Elem())
+		copies[k] = c
+		c.Elem().Set(deepCopy(v.Elem(), copies))
+		return c
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopy(v.Elem(), copies))
+		return c
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+
+		k := visitKey{v.Type(), v.Pointer(), 0}
+		if c, ok := copies[k]; ok {
+			return c
+		}
+
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		copies[k] = c
+
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), deepCopy(iter.Value(), copies))
+		}
+		return c
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+
+		k := visitKey{v.Type(), v.Pointer(), v.Len()}
+		if c, ok := copies[k]; ok {
+			return c
+		}
+
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		copies[k] = c
+
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i), copies))
+		}
+		return c
+
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i), copies))
+		}
+		return c
+
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(deepCopy(v.Field(i), copies))
+			}
+		}
+		return c
+	}
+
+	return v
+}
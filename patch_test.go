@@ -0,0 +1,194 @@
+package access
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type PatchCase struct {
+	Doc      string
+	Patch    string
+	Expected string
+	Invalid  bool
+}
+
+func TestApplyPatch(t *testing.T) {
+
+	//examples from RFC 6902 appendix A
+	cases := []PatchCase{
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`, false},
+		{`{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`, false},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`, false},
+		{`{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`, false},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`, false},
+		{`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`, false},
+		{`{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`, false},
+		{`{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+			`{"baz":"qux","foo":["a",2,"c"]}`, false},
+		{`{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]`, ``, true},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"foo":"bar","child":{"grandchild":{}}}`, false},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`, ``, true},
+		{`{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10}]`, `{"/":9,"~1":10}`, false},
+		{`{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":"10"}]`, ``, true},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`, false},
+
+		//other cases
+		{`{"foo":"bar"}`, `[{"op":"add","path":"","value":[1]}]`, `[1]`, false},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/2","value":"baz"}]`, ``, true},
+		{`{"foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, ``, true},
+		{`{"foo":"bar"}`, `[{"op":"replace","path":"/baz","value":1}]`, ``, true},
+		{`{"foo":{"bar":1}}`, `[{"op":"copy","from":"/foo","path":"/baz"},{"op":"replace","path":"/baz/bar","value":2}]`,
+			`{"foo":{"bar":1},"baz":{"bar":2}}`, false},
+		{`{"foo":{"bar":1}}`, `[{"op":"move","from":"/foo","path":"/foo/bar"}]`, ``, true},
+		{`{"foo":{"bar":[1,{"a":null}]}}`, `[{"op":"test","path":"/foo","value":{"bar":[1.0,{"a":null}]}}]`, `{"foo":{"bar":[1,{"a":null}]}}`, false},
+		{`{"foo":1}`, `[{"op":"unknown","path":"/foo"}]`, ``, true},
+		{`{"foo":1}`, `[{"op":"add","path":"foo","value":1}]`, ``, true},
+		{`{"0":"zero"}`, `[{"op":"remove","path":"/0"}]`, `{}`, false},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":null}]`, `{"foo":"bar","baz":null}`, false},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz"}]`, ``, true},
+		{`{"foo":null}`, `[{"op":"test","path":"/foo","value":null}]`, `{"foo":null}`, false},
+		{`{"foo":null}`, `[{"op":"test","path":"/foo"}]`, ``, true},
+		{`{"foo":"bar"}`, `[{"op":"replace","path":"/foo"}]`, ``, true},
+	}
+
+	for _, c := range cases {
+		var doc, expected interface{}
+		var patch []Operation
+
+		assert.NoError(t, json.Unmarshal([]byte(c.Doc), &doc))
+		assert.NoError(t, json.Unmarshal([]byte(c.Patch), &patch))
+
+		err := ApplyPatch(&doc, patch)
+
+		if c.Invalid {
+			assert.Error(t, err, c.Patch)
+			assert.IsType(t, Error{}, err, c.Patch)
+			continue
+		}
+
+		assert.NoError(t, err, c.Patch)
+		assert.NoError(t, json.Unmarshal([]byte(c.Expected), &expected))
+		assert.Equal(t, expected, doc, c.Patch)
+	}
+}
+
+type patchUser struct {
+	Name   string
+	Emails []string
+	Meta   map[string]interface{}
+}
+
+func TestApplyPatchAtomic(t *testing.T) {
+	assert := assert.New(t)
+
+	user := &patchUser{"foo", []string{"a@example.com"}, map[string]interface{}{"age": 30}}
+
+	assert.NoError(ApplyPatch(user, []Operation{
+		{Op: "replace", Path: "/name", Value: "bar"},
+		{Op: "add", Path: "/emails/0", Value: "b@example.com"},
+		{Op: "remove", Path: "/meta/age"},
+	}))
+	assert.Equal(&patchUser{"bar", []string{"b@example.com", "a@example.com"}, map[string]interface{}{}}, user)
+
+	emails, meta := user.Emails, user.Meta
+
+	err := ApplyPatch(user, []Operation{
+		{Op: "replace", Path: "/name", Value: "baz"},
+		{Op: "add", Path: "/meta/age", Value: 40},
+		{Op: "replace", Path: "/emails/0", Value: "c@example.com"},
+		{Op: "add", Path: "/missing/field", Value: 1},
+	})
+
+	if assert.IsType(Error{}, err) {
+		assert.Equal([]interface{}{"missing", "field"}, err.(Error).Path)
+		assert.Contains(err.Error(), "add operation #3 failed")
+	}
+
+	assert.Equal(&patchUser{"bar", []string{"b@example.com", "a@example.com"}, map[string]interface{}{}}, user)
+	assert.Equal([]string{"b@example.com", "a@example.com"}, emails)
+	assert.Equal(map[string]interface{}{}, meta)
+
+	assert.Error(ApplyPatch(*user, nil))
+}
+
+func TestApplyPatchIndexWriter(t *testing.T) {
+	assert := assert.New(t)
+
+	doc := struct{ Tags *LenIndexes }{&LenIndexes{Indexes{[]string{"a", "c"}}}}
+
+	assert.NoError(ApplyPatch(&doc, []Operation{
+		{Op: "add", Path: "/tags/1", Value: "b"},
+		{Op: "add", Path: "/tags/0", Value: "0"},
+		{Op: "add", Path: "/tags/4", Value: "d"},
+	}))
+	assert.Equal([]string{"0", "a", "b", "c", "d"}, doc.Tags.Slice)
+}
+
+func TestOperationJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	data, err := json.Marshal(Operation{Op: "add", Path: "/a", Value: nil})
+	assert.NoError(err)
+	assert.JSONEq(`{"op":"add","path":"/a","value":null}`, string(data))
+
+	var op Operation
+	assert.NoError(json.Unmarshal(data, &op))
+	assert.Equal(Operation{Op: "add", Path: "/a", Value: json.RawMessage(`null`)}, op)
+
+	assert.NoError(json.Unmarshal([]byte(`{"op":"add","path":"/a"}`), &op))
+	assert.True(op.noValue)
+
+	assert.NoError(json.Unmarshal([]byte(`{"op":"add","path":"/a","value":{"b":[1]}}`), &op))
+	assert.Equal(json.RawMessage(`{"b":[1]}`), op.Value)
+
+	data, err = json.Marshal(op)
+	assert.NoError(err)
+	assert.JSONEq(`{"op":"add","path":"/a","value":{"b":[1]}}`, string(data))
+}
+
+type patchItem struct {
+	SKU   string
+	Count int
+	Price *float64
+}
+
+type patchOrder struct {
+	Items []patchItem
+	Meta  map[string]interface{}
+	Note  interface{}
+}
+
+func TestApplyPatchTyped(t *testing.T) {
+	assert := assert.New(t)
+
+	order := &patchOrder{Items: []patchItem{{SKU: "a", Count: 1}}, Meta: map[string]interface{}{}}
+	price := 2.5
+
+	var patch []Operation
+	assert.NoError(json.Unmarshal([]byte(`[
+		{"op": "replace", "path": "/Items/0/Count", "value": 5},
+		{"op": "add", "path": "/Items/-", "value": {"SKU": "c", "Count": 3}},
+		{"op": "add", "path": "/Items/1", "value": {"SKU": "b", "Count": 2, "Price": 2.5}},
+		{"op": "test", "path": "/Items/1", "value": {"SKU": "b", "Count": 2.0, "Price": 2.5}},
+		{"op": "add", "path": "/Meta/-", "value": [1]},
+		{"op": "add", "path": "/Meta/0", "value": "zero"},
+		{"op": "replace", "path": "/Note", "value": {"a": 1}}
+	]`), &patch))
+
+	assert.NoError(ApplyPatch(order, patch))
+	assert.Equal(&patchOrder{
+		Items: []patchItem{{"a", 5, nil}, {"b", 2, &price}, {"c", 3, nil}},
+		Meta:  map[string]interface{}{"-": []interface{}{1.0}, "0": "zero"},
+		Note:  map[string]interface{}{"a": 1.0},
+	}, order)
+
+	for _, p := range []string{
+		`[{"op": "replace", "path": "/Items/0/Count", "value": "five"}]`,
+		`[{"op": "test", "path": "/Items/0", "value": {"SKU": "a"}}]`,
+	} {
+		assert.NoError(json.Unmarshal([]byte(p), &patch))
+		assert.Error(ApplyPatch(order, patch), p)
+	}
+}
@@ -0,0 +1,193 @@
+package access
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultPathCacheSize is number of parsed selectors cached by default.
+const DefaultPathCacheSize = 1024
+
+// CacheStats describes usage of the cache of parsed selectors.
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	// Len is number of cached selectors
+	Len int
+	// Size is maximum number of cached selectors
+	Size int
+}
+
+// pathCache is a cache of paths parsed from selectors. Hits only take a read lock and mark
+// the entry as referenced, entries are evicted in CLOCK order approximating least recently used.
+type pathCache struct {
+	mu      sync.RWMutex
+	size    int
+	entries map[string]*pathCacheEntry
+	// ring lists entries in insertion order, hand points to the next candidate for eviction
+	ring      []*pathCacheEntry
+	hand      int
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions uint64
+}
+
+type pathCacheEntry struct {
+	selector string
+	path     Path
+	segments []segment
+	// referenced is set by hits, giving the entry a second chance before eviction
+	referenced atomic.Bool
+}
+
+var selectorCache = newPathCache(DefaultPathCacheSize)
+
+func newPathCache(size int) *pathCache {
+	return &pathCache{size: size, entries: map[string]*pathCacheEntry{}}
+}
+
+// SetPathCacheSize limits number of cached selectors, evicting ones not used recently. 0 disables the cache.
+func SetPathCacheSize(size int) {
+	selectorCache.resize(size)
+}
+
+// PathCacheStats returns usage of the cache of parsed selectors since the program started.
+func PathCacheStats() CacheStats {
+	return selectorCache.statistics()
+}
+
+func (c *pathCache) get(selector string) (*pathCacheEntry, bool) {
+	c.mu.RLock()
+	e, ok := c.entries[selector]
+	c.mu.RUnlock()
+
+	if !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	c.hits.Add(1)
+	if !e.referenced.Load() {
+		e.referenced.Store(true)
+	}
+
+	return e, true
+}
+
+func (c *pathCache) add(entry *pathCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.size <= 0 {
+		return
+	}
+
+	if _, ok := c.entries[entry.selector]; ok {
+		return
+	}
+
+	if len(c.ring) >= c.size {
+		c.evict(len(c.ring) - c.size + 1)
+	}
+
+	// new entry takes place right behind the hand, so it's the last candidate for eviction
+	c.ring = append(c.ring, nil)
+	copy(c.ring[c.hand+1:], c.ring[c.hand:])
+	c.ring[c.hand] = entry
+	c.hand = (c.hand + 1) % len(c.ring)
+
+	c.entries[entry.selector] = entry
+}
+
+// lookup returns cached entry of selector s, parsing it when missing.
+func (c *pathCache) lookup(s string) (*pathCacheEntry, error) {
+
+	if e, ok := c.get(s); ok {
+		return e, nil
+	}
+
+	p, err := parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &pathCacheEntry{selector: s, path: p, segments: segments(p)}
+	c.add(e)
+
+	return e, nil
+}
+
+func (c *pathCache) resize(size int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.size = size
+	if size < 0 {
+		size = 0
+	}
+	if len(c.ring) > size {
+		c.evict(len(c.ring) - size)
+	}
+}
+
+// evict removes n entries, skipping and clearing referenced ones.
+func (c *pathCache) evict(n int) {
+	for ; n > 0 && len(c.ring) > 0; n-- {
+		for {
+			if c.hand >= len(c.ring) {
+				c.hand = 0
+			}
+
+			e := c.ring[c.hand]
+			if !e.referenced.Load() {
+				break
+			}
+
+			e.referenced.Store(false)
+			c.hand++
+		}
+
+		delete(c.entries, c.ring[c.hand].selector)
+		c.ring = append(c.ring[:c.hand], c.ring[c.hand+1:]...)
+		c.evictions++
+	}
+
+	if c.hand >= len(c.ring) {
+		c.hand = 0
+	}
+}
+
+func (c *pathCache) statistics() CacheStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return CacheStats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions,
+		Len:       len(c.ring),
+		Size:      c.size,
+	}
+}
+
+// parseCached builds a Path from string selector v using the cache. The returned path is shared
+// and must not be modified, so it's copied before being handed to custom readers, writers or callers.
+func parseCached(v interface{}) (Path, error) {
+
+	if p, ok := v.(Path); ok {
+		return p, nil
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return parse(v)
+	}
+
+	e, err := selectorCache.lookup(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.path, nil
+}
@@ -0,0 +1,200 @@
+package access
+
+import (
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestPathCache(t *testing.T) {
+	assert := assert.New(t)
+
+	c := newPathCache(2)
+
+	_, ok := c.get("a")
+	assert.False(ok)
+
+	c.add(&pathCacheEntry{selector: "a", path: Path{"a"}})
+	c.add(&pathCacheEntry{selector: "b", path: Path{"b"}})
+
+	e, ok := c.get("a")
+	assert.True(ok)
+	assert.Equal(Path{"a"}, e.path)
+
+	// a was referenced since added, so b is evicted first
+	c.add(&pathCacheEntry{selector: "c", path: Path{"c"}})
+	_, ok = c.get("b")
+	assert.False(ok)
+	_, ok = c.get("a")
+	assert.True(ok)
+
+	assert.Equal(CacheStats{Hits: 2, Misses: 2, Evictions: 1, Len: 2, Size: 2}, c.statistics())
+
+	c.resize(1)
+	assert.Equal(CacheStats{Hits: 2, Misses: 2, Evictions: 2, Len: 1, Size: 1}, c.statistics())
+	_, ok = c.get("a")
+	assert.True(ok)
+
+	c.resize(0)
+	c.add(&pathCacheEntry{selector: "d", path: Path{"d"}})
+	assert.Equal(0, c.statistics().Len)
+}
+
+func TestSelectorCache(t *testing.T) {
+	assert := assert.New(t)
+
+	before := PathCacheStats()
+
+	p, err := Parse("cache.test[0]")
+	assert.NoError(err)
+	p[0] = "modified"
+
+	p, err = Parse("cache.test[0]")
+	assert.NoError(err)
+	assert.Equal(Path{"cache", "test", 0}, p)
+
+	after := PathCacheStats()
+	assert.Equal(before.Misses+1, after.Misses)
+	assert.Equal(before.Hits+1, after.Hits)
+	assert.Equal(DefaultPathCacheSize, after.Size)
+
+	// invalid selectors aren't cached
+	_, err = Parse("cache[")
+	assert.Error(err)
+	_, err = Parse("cache[")
+	assert.Error(err)
+	assert.Equal(after.Misses+2, PathCacheStats().Misses)
+
+	SetPathCacheSize(0)
+	defer SetPathCacheSize(DefaultPathCacheSize)
+
+	assert.Equal(0, PathCacheStats().Len)
+	assert.Equal(1, MustRead("a", map[string]interface{}{"a": 1}))
+	assert.Equal(0, PathCacheStats().Len)
+
+	var wg sync.WaitGroup
+	SetPathCacheSize(8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				v := map[string]interface{}{}
+				s := "key" + strconv.Itoa((i+j)%16)
+				assert.NoError(Write(s, &v, j))
+				assert.Equal(j, MustRead(s, v))
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(8, PathCacheStats().Len)
+}
+
+// mutatingReader modifies paths it's given, which mustn't affect cached selectors.
+type mutatingReader struct {
+	paths []Path
+}
+
+func (r *mutatingReader) ReadPath(p Path) (interface{}, error) {
+	r.paths = append(r.paths, append(Path{}, p...))
+	p[0] = "modified"
+	return len(r.paths), nil
+}
+
+func (r *mutatingReader) WritePath(p Path, v interface{}) error {
+	r.paths = append(r.paths, append(Path{}, p...))
+	p[0] = "modified"
+	return nil
+}
+
+func TestSelectorCacheShared(t *testing.T) {
+	assert := assert.New(t)
+
+	r := &mutatingReader{}
+	v := map[string]interface{}{"reader": r, "empty": nil}
+
+	for i := 1; i <= 2; i++ {
+		assert.Equal(i, MustRead("reader.shared[0]", v))
+	}
+	assert.NoError(Write("reader.shared[0]", &v, 1))
+	assert.Equal([]Path{{"shared", 0}, {"shared", 0}, {"shared", 0}}, r.paths)
+
+	matches, err := ReadAll("reader.shared[0]", v)
+	assert.NoError(err)
+	matches[0].Path[0] = "modified"
+
+	p, err := Parse("reader.shared[0]")
+	assert.NoError(err)
+	assert.Equal(Path{"reader", "shared", 0}, p)
+
+	// errors carry a copy as well
+	err = Delete("empty[0]", &v)
+	var e Error
+	if assert.ErrorAs(err, &e) && assert.Len(e.Path, 1) {
+		e.Path[0] = "modified"
+	}
+	assert.Equal(Path{"empty", 0}, New("empty[0]"))
+}
+
+// segmentRegex is the regular expression scanSegment replaced.
+var segmentRegex = regexp.MustCompile(`^(?P<field>\w+|\*)?(?:(?P<index>\[(?:-?\d*:-?\d*(?::-?\d*)?|-?\d+|-|\*)\])|(?P<filter>\[\?)|(?P<key>\[(?:"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*')\]))?(?P<dot>\.?)`)
+
+func TestScanSegment(t *testing.T) {
+	assert := assert.New(t)
+
+	selectors := []string{
+		"", "a", "a.b", "abc_1[0]", "*", "*.a", "a*", "[*]", "[-]", "[-1]", "[--1]", "[]", "[1:2]", "[:]",
+		"[::]", "[1:2:3:4]", "[-1:-2:-3]", "[1a]", "[1", "[?@.a]", `["a.b"]`, `['a']`, `["a\"b"]`, `['a\'b']`,
+		`["a`, `["a"`, `["a\`, "[\"a\\\nb\"]", `[x]`, ".a", "a..b", "a.", "a[0].b", "é", "a[0][1]", `a["]"]`,
+	}
+
+	for _, s := range selectors {
+		seg, n := scanSegment(s)
+
+		match := segmentRegex.FindStringSubmatch(s)
+		expected := pathSegment{field: match[1], filter: match[3] != "", quoted: match[4] != "", dot: match[5] != ""}
+		if match[2] != "" {
+			expected.index = match[2][1 : len(match[2])-1]
+		}
+		if match[4] != "" {
+			expected.key = match[4][1 : len(match[4])-1]
+		}
+
+		assert.Equal(expected, seg, s)
+		assert.Equal(len(match[0]), n, s)
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		parse(`items[0].attributes["key"].values[1:3]`)
+	}
+}
+
+func BenchmarkParseCached(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		parseCached(`items[0].attributes["key"].values[1:3]`)
+	}
+}
+
+func BenchmarkParseCachedParallel(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			parseCached(`items[0].attributes["key"].values[1:3]`)
+		}
+	})
+}
+
+func BenchmarkReadSelector(b *testing.B) {
+	v := map[string]interface{}{"items": []interface{}{map[string]interface{}{"name": "foo"}}}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Read("items[0].name", v)
+	}
+}
@@ -0,0 +1,139 @@
+package access
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var pointerIndexRegex = regexp.MustCompile(`^(?:0|[1-9][0-9]*)$`)
+
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// FromPointer builds a Path from RFC 6901 JSON Pointer like `/a/0/b~1c`.
+// Numeric tokens become indices and `-` becomes Append, ApplyPatch resolves them
+// against the document, so that they address members of objects there.
+func FromPointer(pointer string) (Path, error) {
+
+	if pointer == "" {
+		return Path{}, nil
+	}
+
+	if pointer[0] != '/' {
+		return nil, &ParseError{pointer, 0, "/"}
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	path := make(Path, 0, len(tokens))
+	offset := 1
+
+	for _, token := range tokens {
+
+		for i := 0; i < len(token); i++ {
+			if token[i] == '~' && (i+1 >= len(token) || (token[i+1] != '0' && token[i+1] != '1')) {
+				return nil, &ParseError{pointer, offset + i, "~0 or ~1"}
+			}
+		}
+
+		switch {
+		case token == "-":
+			path = append(path, Append{})
+		case pointerIndexRegex.MatchString(token):
+			index, err := strconv.Atoi(token)
+			if err != nil {
+				return nil, &ParseError{pointer, offset, "index"}
+			}
+			path = append(path, index)
+		default:
+			path = append(path, pointerUnescaper.Replace(token))
+		}
+
+		offset += len(token) + 1
+	}
+
+	return path, nil
+}
+
+// Pointer formats path as RFC 6901 JSON Pointer, failing with error matching ErrUnsupportedKind
+// for elements which pointers can't express, like wildcards, ranges or negative indices.
+func (p Path) Pointer() (string, error) {
+	var b strings.Builder
+
+	for i, e := range p {
+		b.WriteByte('/')
+
+		switch s := e.(type) {
+		case string:
+			b.WriteString(pointerEscaper.Replace(s))
+		case int:
+			if s < 0 {
+				return "", pointerError(p, i)
+			}
+			b.WriteString(strconv.Itoa(s))
+		case Append:
+			b.WriteByte('-')
+		default:
+			return "", pointerError(p, i)
+		}
+	}
+
+	return b.String(), nil
+}
+
+func pointerError(p Path, i int) error {
+	return Error{kindError{fmt.Errorf("Path element `%s` can't be expressed in JSON Pointer", p[i:i+1]), ErrUnsupportedKind}, append([]interface{}{}, p[:i]...)}
+}
+
+// pointerPath builds a Path from JSON Pointer resolving its tokens against doc, so that
+// numeric tokens and `-` address elements of arrays and members of other values.
+func pointerPath(a *Accessor, doc interface{}, pointer string) (Path, error) {
+
+	path, err := FromPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	v := reflect.ValueOf(doc)
+
+	for i, e := range path {
+
+		if !pointerArray(v) {
+			switch s := e.(type) {
+			case int:
+				path[i] = strconv.Itoa(s)
+			case Append:
+				path[i] = "-"
+			}
+		}
+
+		// values which don't exist are reported by the operation using the path
+		if v, err = path[i:i+1].read(a, v); err != nil {
+			break
+		}
+	}
+
+	return path, nil
+}
+
+// pointerArray reports whether pointer tokens address elements of v by index.
+func pointerArray(v reflect.Value) bool {
+
+	if !v.IsValid() {
+		return false
+	}
+
+	if _, ok := indirectRead(v, indexReaderInterface).Interface().(IndexReader); ok {
+		return true
+	}
+
+	for (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	return v.Kind() == reflect.Slice || v.Kind() == reflect.Array
+}
@@ -0,0 +1,98 @@
+package access
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFromPointer(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		Pointer  string
+		Expected Path
+	}{
+		{"", Path{}},
+		{"/", Path{""}},
+		{"/foo", Path{"foo"}},
+		{"/foo/0", Path{"foo", 0}},
+		{"/a~1b", Path{"a/b"}},
+		{"/m~0n", Path{"m~n"}},
+		{"/~01", Path{"~1"}},
+		{"/a/0/b~1c", Path{"a", 0, "b/c"}},
+		{"/ ", Path{" "}},
+		{"/c%d", Path{"c%d"}},
+		{"/01", Path{"01"}},
+		{"/-1", Path{"-1"}},
+		{"/list/-", Path{"list", Append{}}},
+	}
+
+	for _, c := range cases {
+		p, err := FromPointer(c.Pointer)
+		assert.NoError(err, c.Pointer)
+		assert.Equal(c.Expected, p, c.Pointer)
+		pointer, err := p.Pointer()
+		assert.NoError(err)
+		assert.Equal(c.Pointer, pointer, c.Pointer)
+	}
+
+	for pointer, offset := range map[string]int{"foo": 0, "/a~2": 2, "/a/b~": 4} {
+		_, err := FromPointer(pointer)
+		if assert.IsType(&ParseError{}, err, pointer) {
+			assert.Equal(offset, err.(*ParseError).Offset, pointer)
+		}
+	}
+
+	for _, p := range []Path{{Wildcard{}, "a"}, {"a", -1}, New("a[0:1]"), New("..a"), New("a[?(@.b)]")} {
+		_, err := p.Pointer()
+		assert.True(errors.Is(err, ErrUnsupportedKind), p.String())
+	}
+}
+
+func TestPointerAccess(t *testing.T) {
+	assert := assert.New(t)
+
+	doc := map[string]interface{}{
+		"foo": []interface{}{"bar", "baz"},
+		"":    0,
+		"a/b": 1,
+		"m~n": 8,
+		"0":   "zero",
+	}
+
+	for pointer, expected := range map[string]interface{}{
+		"/foo/0": "bar",
+		"/":      0,
+		"/a~1b":  1,
+		"/m~0n":  8,
+		"/0":     "zero",
+	} {
+		p, err := pointerPath(defaultAccessor(), doc, pointer)
+		assert.NoError(err)
+		assert.Equal(expected, p.MustRead(doc), pointer)
+	}
+
+	p, _ := pointerPath(defaultAccessor(), doc, "/foo/-")
+	assert.NoError(p.Write(&doc, "qux"))
+	assert.Equal([]interface{}{"bar", "baz", "qux"}, doc["foo"])
+
+	// numeric tokens and `-` are member names of objects
+	for pointer, expected := range map[string]Path{"/1": {"1"}, "/-": {"-"}, "/foo/1": {"foo", 1}} {
+		p, err := pointerPath(defaultAccessor(), doc, pointer)
+		assert.NoError(err)
+		assert.Equal(expected, p, pointer)
+	}
+
+	p, _ = pointerPath(defaultAccessor(), doc, "/1")
+	assert.NoError(p.Write(&doc, "one"))
+	assert.Equal("one", doc["1"])
+
+	// paths don't address map members by index
+	_, err := FromPointer("/0")
+	assert.NoError(err)
+	_, err = Read("[0]", doc)
+	assert.True(errors.Is(err, ErrUnsupportedKind))
+	assert.Error(Write("m[-1]", &doc, 1))
+	assert.NotContains(doc, "-1")
+}
@@ -0,0 +1,337 @@
+package access
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Range is a path element selecting slice of elements like `[start:end:step]`.
+// Nil bounds are open, negative bounds count from the end, nil step means 1.
+type Range struct {
+	Start *int
+	End   *int
+	Step  *int
+}
+
+func (r Range) String() string {
+	bound := func(b *int) string {
+		if b == nil {
+			return ""
+		}
+		return strconv.Itoa(*b)
+	}
+
+	s := bound(r.Start) + ":" + bound(r.End)
+	if r.Step != nil {
+		s += ":" + bound(r.Step)
+	}
+	return s
+}
+
+// parseRange parses `start:end:step` range without brackets.
+func parseRange(s string) (Range, error) {
+	var (
+		r     Range
+		parts = strings.Split(s, ":")
+		refs  = []**int{&r.Start, &r.End, &r.Step}
+	)
+
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return r, err
+		}
+		*refs[i] = &n
+	}
+
+	return r, nil
+}
+
+// bounds normalizes range against sequence length the way Python slices do.
+func (r Range) bounds(length int) (start, end, step int, err error) {
+	step = 1
+	if r.Step != nil {
+		step = *r.Step
+	}
+
+	if step == 0 {
+		return 0, 0, 0, fmt.Errorf("Range step can't be zero")
+	}
+
+	clamp := func(i, min, max int) int {
+		if i < 0 {
+			i += length
+		}
+		if i < min {
+			return min
+		}
+		if i > max {
+			return max
+		}
+		return i
+	}
+
+	if step > 0 {
+		start, end = 0, length
+		if r.Start != nil {
+			start = clamp(*r.Start, 0, length)
+		}
+		if r.End != nil {
+			end = clamp(*r.End, 0, length)
+		}
+	} else {
+		start, end = length-1, -1
+		if r.Start != nil {
+			start = clamp(*r.Start, -1, length-1)
+		}
+		if r.End != nil {
+			end = clamp(*r.End, -1, length-1)
+		}
+	}
+
+	return start, end, step, nil
+}
+
+// indices lists positions selected by range in sequence of given length.
+func (r Range) indices(length int) ([]int, error) {
+	start, end, step, err := r.bounds(length)
+	if err != nil {
+		return nil, err
+	}
+
+	indices := []int{}
+	for i := start; (step > 0 && i < end) || (step < 0 && i > end); i += step {
+		indices = append(indices, i)
+	}
+
+	return indices, nil
+}
+
+func readRange(a *Accessor, v reflect.Value, rng Range, path *Path) (reflect.Value, error) {
+	if !v.IsValid() {
+		return reflect.Value{}, kindError{fmt.Errorf("slice, array or IndexReader instance expected"), ErrUnsupportedKind}
+	}
+
+	v = indirectRead(v, indexReaderInterface)
+
+	if r, ok := v.Interface().(IndexReader); ok {
+		l, ok := r.(Lener)
+		if !ok {
+			return reflect.Value{}, kindError{fmt.Errorf("Range %s requires %T to implement Len() int", rng, r), ErrUnsupportedKind}
+		}
+
+		indices, err := rng.indices(l.Len())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		values := make([]interface{}, len(indices))
+		for n, i := range indices {
+			if values[n], err = r.Index(i); err != nil {
+				return reflect.Value{}, err
+			}
+		}
+
+		rv := reflect.ValueOf(values)
+		if path != nil {
+			return path.read(a, rv)
+		}
+		return rv, nil
+	}
+
+	vt := v.Type()
+
+	switch vt.Kind() {
+	case reflect.Interface:
+		return readRange(a, v.Elem(), rng, path)
+	case reflect.Array, reflect.Slice:
+
+		indices, err := rng.indices(v.Len())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		rv := reflect.MakeSlice(reflect.SliceOf(vt.Elem()), 0, len(indices))
+		for _, i := range indices {
+			rv = reflect.Append(rv, v.Index(i))
+		}
+
+		if path != nil {
+			return path.read(a, rv)
+		}
+		return rv, nil
+	default:
+		return reflect.Value{}, kindError{fmt.Errorf("slice, array or IndexReader instance expected"), ErrUnsupportedKind}
+	}
+}
+
+// rangeElements converts written slice or array into values of given element type.
+func rangeElements(a *Accessor, w reflect.Value, et reflect.Type) ([]reflect.Value, error) {
+	if !w.IsValid() {
+		return nil, nil
+	}
+
+	if w.Kind() == reflect.Ptr {
+		w = w.Elem()
+	}
+
+	if w.Kind() != reflect.Slice && w.Kind() != reflect.Array {
+		return nil, kindError{fmt.Errorf("slice or array value expected to write range"), ErrTypeMismatch}
+	}
+
+	elems := make([]reflect.Value, w.Len())
+	for i := range elems {
+		e := w.Index(i)
+		if e.Kind() == reflect.Interface {
+			e = e.Elem()
+		}
+
+		var wt reflect.Type
+		if e.IsValid() {
+			wt = e.Type()
+		}
+
+		elems[i] = reflect.New(et).Elem()
+		if err := indirectWrite(a, elems[i], e, wt); err != nil {
+			return nil, Error{err, []interface{}{i}}
+		}
+	}
+
+	return elems, nil
+}
+
+func writeRange(a *Accessor, v reflect.Value, rng Range, path *Path, w reflect.Value, wt reflect.Type) error {
+
+	// allocate nil pointers, assigning them only when write succeeded
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		if !a.allocate {
+			return allocationError(v.Type())
+		}
+
+		e := reflect.New(v.Type().Elem()).Elem()
+
+		if err := writeRange(a, e, rng, path, w, wt); err != nil {
+			return err
+		}
+
+		return indirectWrite(a, v, e, e.Type())
+	}
+
+	v = indirectRead(v, indexWriterInterface)
+
+	if path != nil {
+		rv, err := readRange(a, v, rng, nil)
+		if err != nil {
+			return err
+		}
+
+		rv = allocateNew(rv)
+		if err := path.write(a, rv, w, wt); err != nil {
+			return err
+		}
+
+		return writeRange(a, v, rng, nil, rv, rv.Type())
+	}
+
+	if r, ok := v.Interface().(IndexWriter); ok {
+		l, ok := r.(Lener)
+		if !ok {
+			return kindError{fmt.Errorf("Range %s requires %T to implement Len() int", rng, r), ErrUnsupportedKind}
+		}
+
+		indices, err := rng.indices(l.Len())
+		if err != nil {
+			return err
+		}
+
+		elems, err := rangeElements(a, w, reflect.TypeOf((*interface{})(nil)).Elem())
+		if err != nil {
+			return err
+		}
+
+		if len(elems) != len(indices) {
+			return kindError{fmt.Errorf("Range %s of %T selects %d elements, got %d", rng, r, len(indices), len(elems)), ErrTypeMismatch}
+		}
+
+		for n, i := range indices {
+			if err := r.SetIndex(i, elems[n].Interface()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	vt := v.Type()
+
+	switch vt.Kind() {
+	case reflect.Interface:
+		var e reflect.Value
+
+		if v.IsNil() && v.NumMethod() == 0 {
+			if !a.allocate {
+				return allocationError(vt)
+			}
+			array := []interface{}{}
+			e = reflect.ValueOf(&array).Elem()
+		} else {
+			e = allocateNew(v.Elem())
+		}
+
+		if err := writeRange(a, e, rng, path, w, wt); err != nil {
+			return err
+		}
+
+		return indirectWrite(a, v, e, e.Type())
+
+	case reflect.Array, reflect.Slice:
+
+		start, end, step, err := rng.bounds(v.Len())
+		if err != nil {
+			return err
+		}
+
+		elems, err := rangeElements(a, w, vt.Elem())
+		if err != nil {
+			return err
+		}
+
+		// contiguous range of slice is spliced, so it may grow or shrink
+		if step == 1 && vt.Kind() == reflect.Slice {
+			if end < start {
+				end = start
+			}
+
+			nv := reflect.MakeSlice(vt, 0, v.Len()-(end-start)+len(elems))
+			nv = reflect.AppendSlice(nv, v.Slice(0, start))
+			nv = reflect.Append(nv, elems...)
+			nv = reflect.AppendSlice(nv, v.Slice(end, v.Len()))
+
+			return indirectWrite(a, v, nv, vt)
+		}
+
+		indices, _ := rng.indices(v.Len())
+
+		if len(elems) != len(indices) {
+			return kindError{fmt.Errorf("Range %s selects %d elements, got %d", rng, len(indices), len(elems)), ErrTypeMismatch}
+		}
+
+		if vt.Kind() == reflect.Array && !v.CanSet() {
+			return kindError{fmt.Errorf("got value that couldn't be changed"), ErrNotAddressable}
+		}
+
+		for n, i := range indices {
+			v.Index(i).Set(elems[n])
+		}
+
+		return nil
+	default:
+		return kindError{fmt.Errorf("slice, array or IndexWriter instance expected"), ErrUnsupportedKind}
+	}
+}
@@ -0,0 +1,127 @@
+package access
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRangeParse(t *testing.T) {
+	assert := assert.New(t)
+
+	one, three, four, two, minus := 1, 3, 4, 2, -1
+
+	assert.Equal(Path{"items", Range{Start: &one, End: &four}}, New("items[1:4]"))
+	assert.Equal(Path{"items", Range{End: &three}}, New("items[:3]"))
+	assert.Equal(Path{"items", Range{Step: &two}}, New("items[::2]"))
+	assert.Equal(Path{Range{Start: &minus}}, New("[-1:]"))
+	assert.Equal(Path{Range{}}, New("[:]"))
+
+	for _, s := range []string{"items[1:4]", "items[:3]", "items[::2]", "[-2::-1].name", "[:]"} {
+		assert.Equal(s, New(s).String())
+	}
+
+	assert.Panics(func() { New("items[1:2:3:4]") })
+	assert.Panics(func() { New("items[a:b]") })
+}
+
+func TestRangeRead(t *testing.T) {
+	assert := assert.New(t)
+
+	s := []int{0, 1, 2, 3, 4, 5}
+	a := [6]int{0, 1, 2, 3, 4, 5}
+
+	cases := []struct {
+		Path     string
+		Expected []int
+	}{
+		{"[1:4]", []int{1, 2, 3}},
+		{"[:3]", []int{0, 1, 2}},
+		{"[::2]", []int{0, 2, 4}},
+		{"[-2:]", []int{4, 5}},
+		{"[::-1]", []int{5, 4, 3, 2, 1, 0}},
+		{"[4:1:-2]", []int{4, 2}},
+		{"[10:]", []int{}},
+		{"[3:1]", []int{}},
+	}
+
+	for _, c := range cases {
+		assert.Equal(c.Expected, MustRead(c.Path, s), c.Path)
+		assert.Equal(c.Expected, MustRead(c.Path, &a), c.Path)
+	}
+
+	_, err := Read("[::0]", s)
+	assert.Error(err)
+
+	data := map[string]interface{}{"items": []interface{}{"a", "b", "c"}}
+	assert.Equal([]interface{}{"b", "c"}, MustRead("items[1:]", data))
+	assert.Equal("c", MustRead("items[1:][1]", data))
+
+	li := LenIndexes{Indexes{[]string{"foo", "bar", "baz"}}}
+	assert.Equal([]interface{}{"bar", "baz"}, MustRead("[1:]", li))
+
+	_, err = Read("[1:]", Indexes{[]string{"foo"}})
+	assert.Error(err)
+}
+
+func TestRangeWrite(t *testing.T) {
+	assert := assert.New(t)
+
+	s := []int{0, 1, 2, 3, 4, 5}
+
+	//grow
+	assert.NoError(Write("[1:3]", &s, []int{7, 8, 9}))
+	assert.Equal([]int{0, 7, 8, 9, 3, 4, 5}, s)
+
+	//shrink
+	assert.NoError(Write("[1:4]", &s, []interface{}{1}))
+	assert.Equal([]int{0, 1, 3, 4, 5}, s)
+
+	//insert
+	assert.NoError(Write("[2:2]", &s, []int{2}))
+	assert.Equal([]int{0, 1, 2, 3, 4, 5}, s)
+
+	//remove
+	assert.NoError(Write("[4:]", &s, nil))
+	assert.Equal([]int{0, 1, 2, 3}, s)
+
+	//extended ranges require the same number of elements
+	assert.NoError(Write("[::2]", &s, []int{10, 12}))
+	assert.Equal([]int{10, 1, 12, 3}, s)
+	assert.Error(Write("[::2]", &s, []int{1}))
+
+	assert.Error(Write("[1:2]", &s, "string"))
+	assert.Error(Write("[1:2]", &s, []string{"string"}))
+	assert.Equal([]int{10, 1, 12, 3}, s)
+
+	//arrays can't change length
+	a := [3]int{0, 1, 2}
+	assert.NoError(Write("[1:]", &a, []int{5, 6}))
+	assert.Equal([3]int{0, 5, 6}, a)
+	assert.Error(Write("[1:]", &a, []int{5}))
+
+	//nested path
+	data := map[string]interface{}{"items": []map[string]int{{"a": 1}, {"a": 2}, {"a": 3}}}
+	assert.NoError(Write("items[1:][0].a", &data, 5))
+	assert.Equal(5, MustRead("items[1].a", data))
+
+	var anything interface{}
+	assert.NoError(Write("[:]", &anything, []string{"a", "b"}))
+	assert.Equal([]interface{}{"a", "b"}, anything)
+
+	//nil pointers are allocated only when write succeeds
+	var ps *[]int
+	assert.Error(Write("[0:0]", &ps, []string{"a"}))
+	assert.Nil(ps)
+	assert.NoError(Write("[0:0]", &ps, []int{1, 2}))
+	assert.Equal(&[]int{1, 2}, ps)
+
+	ps = nil
+	assert.Error(NewAccessor(WithAllocation(false)).Write("[0:0]", &ps, []int{1}))
+	assert.Nil(ps)
+
+	//IndexWriter
+	li := &LenIndexes{Indexes{[]string{"foo", "bar", "baz"}}}
+	assert.NoError(Write("[1:]", li, []string{"qux", "quux"}))
+	assert.Equal([]string{"foo", "qux", "quux"}, li.Slice)
+	assert.Error(Write("[1:]", li, []string{"qux"}))
+}
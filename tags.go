@@ -0,0 +1,56 @@
+package access
+
+import (
+	"reflect"
+	"strings"
+)
+
+// fieldName returns name of field given by its tag and whether the field is hidden.
+func fieldName(a *Accessor, f reflect.StructField) (string, bool) {
+
+	for _, tag := range a.tags {
+
+		value, ok := f.Tag.Lookup(tag)
+		if !ok {
+			continue
+		}
+
+		if value == "-" {
+			return "", true
+		}
+
+		if name := strings.Split(value, ",")[0]; name != "" {
+			return name, false
+		}
+	}
+
+	return "", false
+}
+
+// findStructField returns index of field in st named by a tag or having Go name matched by NameMatcher, or -1.
+func findStructField(a *Accessor, st *structTable, name string) int {
+
+	if i, ok := st.tagged[name]; ok {
+		return i
+	}
+
+	m := a.matcher
+
+	if i, ok := st.goNames[name]; ok && m.Match(name, name) {
+		return i
+	}
+
+	if goName := camelcased(name); goName != name {
+		if i, ok := st.goNames[goName]; ok && m.Match(name, goName) {
+			return i
+		}
+	}
+
+	for i, f := range st.fields {
+		if m.Match(name, f.Name) {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,76 @@
+package access
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type taggedBase struct {
+	CreatedAt string `json:"created"`
+}
+
+type taggedUser struct {
+	taggedBase
+	Login    string `json:"login" yaml:"user"`
+	Email    string `json:"email_address,omitempty" access:"mail"`
+	Password string `json:"-"`
+	Age      int    `json:",omitempty" mapstructure:"years"`
+	Hidden   string `access:"-" json:"hidden"`
+}
+
+func TestTags(t *testing.T) {
+	assert := assert.New(t)
+
+	user := &taggedUser{taggedBase{"today"}, "foo", "foo@example.com", "secret", 20, "x"}
+
+	// tags are ignored unless enabled
+	assert.Equal("secret", MustRead("password", user))
+	assert.Equal("x", MustRead("hidden", user))
+	assert.Nil(MustRead("mail", user))
+	assert.NoError(Write("password", user, "changed"))
+	assert.Equal("changed", user.Password)
+
+	SetTagNames("access", "json")
+	defer SetTagNames()
+
+	assert.Equal("foo", MustRead("login", user))
+	assert.Equal("foo", MustRead("Login", user))
+	assert.Equal("foo@example.com", MustRead("mail", user))
+	assert.Equal("foo@example.com", MustRead("email", user))
+	assert.Nil(MustRead("email_address", user))
+	assert.Equal(20, MustRead("age", user))
+	assert.Equal("today", MustRead("created", user))
+	assert.Equal("today", MustRead("created_at", user))
+
+	_, err := Read("password", user)
+	assert.True(errors.Is(err, ErrNotFound))
+	assert.Error(Write("password", user, "changed"))
+	assert.False(Exists("hidden", user))
+
+	assert.NoError(Write("mail", user, "bar@example.com"))
+	assert.Equal("bar@example.com", user.Email)
+
+	matches, err := ReadAll("*", user)
+	assert.NoError(err)
+
+	keys := []interface{}{}
+	for _, m := range matches {
+		keys = append(keys, m.Path[0])
+	}
+	assert.Equal([]interface{}{"login", "mail", "Age"}, keys)
+
+	SetTagNames("yaml", "mapstructure")
+
+	assert.Equal("foo", MustRead("user", user))
+	assert.Equal(20, MustRead("years", user))
+	assert.Equal("changed", MustRead("password", user))
+	assert.Equal("x", MustRead("hidden", user))
+	assert.Nil(MustRead("mail", user))
+
+	SetTagNames()
+
+	assert.Equal("bar@example.com", MustRead("email", user))
+	assert.Nil(MustRead("login_name", user))
+	assert.Nil(MustRead("user", user))
+}
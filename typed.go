@@ -0,0 +1,361 @@
+package access
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sync/atomic"
+)
+
+var (
+	stringType  = reflect.TypeOf("")
+	int64Type   = reflect.TypeOf(int64(0))
+	float64Type = reflect.TypeOf(float64(0))
+	boolType    = reflect.TypeOf(false)
+	msiType     = reflect.TypeOf(map[string]interface{}{})
+)
+
+type segmentKind uint8
+
+const (
+	segmentField segmentKind = iota
+	segmentIndex
+	segmentOther
+)
+
+// segment is a path element prepared for reading without allocations.
+type segment struct {
+	kind  segmentKind
+	name  string
+	index int
+	// key is name as a string map key
+	key reflect.Value
+	// resolved caches how the segment applies to types it was read from by accessors,
+	// the list is replaced as a whole when a resolution is added
+	resolved atomic.Pointer[[]*resolvedSegment]
+}
+
+// maxSegmentResolutions limits number of resolutions cached by a segment, dropping the oldest ones.
+const maxSegmentResolutions = 8
+
+// resolvedSegment describes how a segment applies to values of a concrete type.
+type resolvedSegment struct {
+	accessor *Accessor
+	typ      reflect.Type
+	// plain is false when values of typ or pointers to them implement custom readers
+	plain bool
+	// field is index of struct field, nil when the struct has no field of that name
+	field []int
+}
+
+// segments prepares elements of path p for reading.
+func segments(p Path) []segment {
+
+	segs := make([]segment, len(p))
+
+	for i, e := range p {
+		switch s := e.(type) {
+		case string:
+			segs[i].kind = segmentField
+			segs[i].name = s
+			segs[i].key = reflect.ValueOf(s)
+		case int:
+			segs[i].kind = segmentIndex
+			segs[i].index = s
+		default:
+			segs[i].kind = segmentOther
+		}
+	}
+
+	return segs
+}
+
+// resolve returns how s applies to values of concrete type t read by a, reusing cached resolutions.
+func (s *segment) resolve(a *Accessor, t reflect.Type) *resolvedSegment {
+
+	old := s.resolved.Load()
+	if old != nil {
+		for _, r := range *old {
+			if r.typ == t && r.accessor == a {
+				return r
+			}
+		}
+	}
+
+	r := &resolvedSegment{
+		accessor: a,
+		typ:      t,
+		plain:    !customAccess(t, pathReaderInterface, fieldReaderInterface, indexReaderInterface),
+	}
+
+	if s.kind == segmentField && t.Kind() == reflect.Struct {
+		if f, ok := structField(a, t, s.name); ok {
+			r.field = f.Index
+		}
+	}
+
+	var rs []*resolvedSegment
+	if old != nil {
+		rs = *old
+		if len(rs) >= maxSegmentResolutions {
+			rs = rs[len(rs)-maxSegmentResolutions+1:]
+		}
+	}
+
+	rs = append(append(make([]*resolvedSegment, 0, len(rs)+1), rs...), r)
+
+	// losing a race only drops this resolution, which is made again by the next read
+	s.resolved.CompareAndSwap(old, &rs)
+
+	return r
+}
+
+// readSegments reads value at segs from v through struct fields, string map keys and slice or array
+// elements, ok is false when the value must be read by Path, which reports failures.
+func (a *Accessor) readSegments(segs []segment, v reflect.Value) (reflect.Value, bool) {
+
+	for i := range segs {
+		s := &segs[i]
+
+		if s.kind == segmentOther {
+			return reflect.Value{}, false
+		}
+
+		var ok bool
+		if v, ok = concreteValue(v); !ok {
+			return reflect.Value{}, false
+		}
+
+		r := s.resolve(a, v.Type())
+		if !r.plain {
+			return reflect.Value{}, false
+		}
+
+		switch v.Kind() {
+		case reflect.Struct:
+			if s.kind != segmentField || r.field == nil {
+				return reflect.Value{}, false
+			}
+
+			if len(r.field) == 1 {
+				v = v.Field(r.field[0])
+			} else if v, ok = fieldByIndex(v, r.field); !ok {
+				return reflect.Value{}, false
+			}
+
+		case reflect.Map:
+			if s.kind != segmentField || v.Type().Key() != stringType {
+				return reflect.Value{}, false
+			}
+
+			// MapIndex copies values which aren't pointers, so decoded JSON objects are indexed directly
+			if v.Type() == msiType && v.CanInterface() {
+				val, found := v.Interface().(map[string]interface{})[s.name]
+				if !found {
+					return reflect.Value{}, false
+				}
+				v = reflect.ValueOf(val)
+				continue
+			}
+
+			if v = v.MapIndex(s.key); !v.IsValid() {
+				return reflect.Value{}, false
+			}
+
+		case reflect.Slice, reflect.Array:
+			if s.kind != segmentIndex {
+				return reflect.Value{}, false
+			}
+
+			index := s.index
+			if index < 0 {
+				index += v.Len()
+			}
+
+			if index < 0 || index >= v.Len() {
+				return reflect.Value{}, false
+			}
+
+			v = v.Index(index)
+
+		default:
+			return reflect.Value{}, false
+		}
+	}
+
+	return v, true
+}
+
+// concreteValue dereferences pointers and interfaces of v, ok is false when one of them is nil.
+func concreteValue(v reflect.Value) (reflect.Value, bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	return v, v.IsValid()
+}
+
+// fieldByIndex returns nested struct field of v, ok is false when an embedded pointer is nil.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 {
+			var ok bool
+			if v, ok = concreteValue(v); !ok || v.Kind() != reflect.Struct {
+				return reflect.Value{}, false
+			}
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// readLeaf reads value at selector s for typed reads, returning the parsed path for errors.
+// Nil values are returned as invalid values.
+func (a *Accessor) readLeaf(s string, v interface{}) (reflect.Value, Path, error) {
+
+	e, err := selectorCache.lookup(s)
+	if err != nil {
+		return reflect.Value{}, nil, err
+	}
+
+	if err := a.checkDepth(e.path); err != nil {
+		return reflect.Value{}, nil, err
+	}
+
+	if rv, ok := a.readSegments(e.segments, reflect.ValueOf(v)); ok {
+		rv, _ = concreteValue(rv)
+		return rv, e.path, nil
+	}
+
+	val, err := a.readPath(e.path, v)
+	if err != nil {
+		return reflect.Value{}, nil, err
+	}
+
+	rv, _ := concreteValue(reflect.ValueOf(val))
+
+	return rv, e.path, nil
+}
+
+// convertLeaf converts v into type t with the converter, failing when it isn't enabled.
+func (a *Accessor) convertLeaf(v reflect.Value, t reflect.Type, path Path) (reflect.Value, error) {
+
+	if a.converter != nil {
+		cv, err := a.converter.convert(v, t)
+		if err != nil {
+			return reflect.Value{}, Error{err, append([]interface{}{}, path...)}
+		}
+		return cv, nil
+	}
+
+	return reflect.Value{}, Error{kindError{fmt.Errorf("Value of type %s is not %s", v.Type(), t), ErrTypeMismatch}, append([]interface{}{}, path...)}
+}
+
+// ReadString reads string at selector s using the default instance, see Accessor.ReadString.
+func ReadString(s string, v interface{}) (string, error) {
+	return defaultAccessor().ReadString(s, v)
+}
+
+// ReadInt64 reads integer at selector s using the default instance, see Accessor.ReadInt64.
+func ReadInt64(s string, v interface{}) (int64, error) {
+	return defaultAccessor().ReadInt64(s, v)
+}
+
+// ReadFloat64 reads number at selector s using the default instance, see Accessor.ReadFloat64.
+func ReadFloat64(s string, v interface{}) (float64, error) {
+	return defaultAccessor().ReadFloat64(s, v)
+}
+
+// ReadBool reads bool at selector s using the default instance, see Accessor.ReadBool.
+func ReadBool(s string, v interface{}) (bool, error) {
+	return defaultAccessor().ReadBool(s, v)
+}
+
+// ReadString reads value of string kind at selector s, nil values yield "".
+func (a *Accessor) ReadString(s string, v interface{}) (string, error) {
+
+	rv, path, err := a.readLeaf(s, v)
+	if err != nil || !rv.IsValid() {
+		return "", err
+	}
+
+	if rv.Kind() != reflect.String {
+		if rv, err = a.convertLeaf(rv, stringType, path); err != nil {
+			return "", err
+		}
+	}
+
+	return rv.String(), nil
+}
+
+// ReadInt64 reads integer at selector s, or unsigned integer and float fitting int64 exactly, nil values yield 0.
+func (a *Accessor) ReadInt64(s string, v interface{}) (int64, error) {
+
+	rv, path, err := a.readLeaf(s, v)
+	if err != nil || !rv.IsValid() {
+		return 0, err
+	}
+
+	switch k := rv.Kind(); {
+	case isInt(k):
+		return rv.Int(), nil
+	case isUint(k):
+		if u := rv.Uint(); u <= math.MaxInt64 {
+			return int64(u), nil
+		}
+	case isFloat(k):
+		if f := rv.Float(); f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+			return int64(f), nil
+		}
+	default:
+		if rv, err = a.convertLeaf(rv, int64Type, path); err != nil {
+			return 0, err
+		}
+		return rv.Int(), nil
+	}
+
+	return 0, Error{kindError{fmt.Errorf("Value %v doesn't fit into int64", rv), ErrTypeMismatch}, append([]interface{}{}, path...)}
+}
+
+// ReadFloat64 reads value of float or integer kind at selector s, nil values yield 0.
+func (a *Accessor) ReadFloat64(s string, v interface{}) (float64, error) {
+
+	rv, path, err := a.readLeaf(s, v)
+	if err != nil || !rv.IsValid() {
+		return 0, err
+	}
+
+	switch k := rv.Kind(); {
+	case isFloat(k):
+		return rv.Float(), nil
+	case isInt(k):
+		return float64(rv.Int()), nil
+	case isUint(k):
+		return float64(rv.Uint()), nil
+	}
+
+	if rv, err = a.convertLeaf(rv, float64Type, path); err != nil {
+		return 0, err
+	}
+
+	return rv.Float(), nil
+}
+
+// ReadBool reads value of bool kind at selector s, nil values yield false.
+func (a *Accessor) ReadBool(s string, v interface{}) (bool, error) {
+
+	rv, path, err := a.readLeaf(s, v)
+	if err != nil || !rv.IsValid() {
+		return false, err
+	}
+
+	if rv.Kind() != reflect.Bool {
+		if rv, err = a.convertLeaf(rv, boolType, path); err != nil {
+			return false, err
+		}
+	}
+
+	return rv.Bool(), nil
+}
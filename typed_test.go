@@ -0,0 +1,248 @@
+package access
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"reflect"
+	"testing"
+)
+
+type typedItem struct {
+	Name   string  `json:"name"`
+	Count  int     `json:"count"`
+	Price  float64 `json:"price"`
+	Active bool    `json:"active"`
+	Next   *typedItem
+	Attrs  map[string]interface{}
+	Tags   []string
+	Big    uint64
+	Label  *string
+	Size   int8
+	typedEmbedded
+}
+
+type typedEmbedded struct {
+	Source string
+}
+
+func newTypedItem() *typedItem {
+	label := "lbl"
+	return &typedItem{
+		Name:   "foo",
+		Count:  3,
+		Price:  1.5,
+		Active: true,
+		Next:   &typedItem{Name: "bar", Count: -1},
+		Attrs: map[string]interface{}{
+			"color": "red",
+			"size":  float64(42),
+			"ratio": 0.5,
+			"on":    true,
+			"nil":   nil,
+			"list":  []interface{}{"a", float64(2)},
+		},
+		Tags:          []string{"x", "y"},
+		Big:           math.MaxUint64,
+		Label:         &label,
+		Size:          8,
+		typedEmbedded: typedEmbedded{"web"},
+	}
+}
+
+func TestTypedReads(t *testing.T) {
+	assert := assert.New(t)
+
+	item := newTypedItem()
+
+	s, err := ReadString("name", item)
+	assert.NoError(err)
+	assert.Equal("foo", s)
+
+	s, err = ReadString("next.name", item)
+	assert.NoError(err)
+	assert.Equal("bar", s)
+
+	s, err = ReadString("attrs.list[0]", item)
+	assert.NoError(err)
+	assert.Equal("a", s)
+
+	s, err = ReadString("tags[-1]", item)
+	assert.NoError(err)
+	assert.Equal("y", s)
+
+	s, err = ReadString("label", item)
+	assert.NoError(err)
+	assert.Equal("lbl", s)
+
+	s, err = ReadString("source", item)
+	assert.NoError(err)
+	assert.Equal("web", s)
+
+	s, err = ReadString("attrs.nil", item)
+	assert.NoError(err)
+	assert.Equal("", s)
+
+	i, err := ReadInt64("count", item)
+	assert.NoError(err)
+	assert.Equal(int64(3), i)
+
+	i, err = ReadInt64("next.count", item)
+	assert.NoError(err)
+	assert.Equal(int64(-1), i)
+
+	i, err = ReadInt64("size", item)
+	assert.NoError(err)
+	assert.Equal(int64(8), i)
+
+	i, err = ReadInt64("attrs.size", item)
+	assert.NoError(err)
+	assert.Equal(int64(42), i)
+
+	_, err = ReadInt64("attrs.ratio", item)
+	assert.True(errors.Is(err, ErrTypeMismatch))
+
+	_, err = ReadInt64("big", item)
+	assert.True(errors.Is(err, ErrTypeMismatch))
+
+	f, err := ReadFloat64("price", item)
+	assert.NoError(err)
+	assert.Equal(1.5, f)
+
+	f, err = ReadFloat64("count", item)
+	assert.NoError(err)
+	assert.Equal(float64(3), f)
+
+	b, err := ReadBool("active", item)
+	assert.NoError(err)
+	assert.True(b)
+
+	b, err = ReadBool("attrs.on", item)
+	assert.NoError(err)
+	assert.True(b)
+
+	_, err = ReadBool("name", item)
+	assert.True(errors.Is(err, ErrTypeMismatch))
+	assert.Equal(Path{"name"}, Path(err.(Error).Path))
+
+	_, err = ReadString("missing", item)
+	assert.True(errors.Is(err, ErrNotFound))
+
+	_, err = ReadString("tags[5]", item)
+	assert.True(errors.Is(err, ErrIndexOutOfRange))
+
+	_, err = ReadString("next.next.name", item)
+	assert.True(errors.Is(err, ErrNotFound))
+
+	_, err = ReadString("name[", item)
+	assert.Error(err)
+
+	// custom readers and getters are read by Path
+	s, err = ReadString("name", &Fields{map[string]interface{}{"name": "custom"}})
+	assert.NoError(err)
+	assert.Equal("custom", s)
+
+	s, err = ReadString("firstname", &Person{"foo", "boo", "baz", nil, nil})
+	assert.NoError(err)
+	assert.Equal("foo", s)
+
+	a := NewAccessor(WithConverter(NewConverter()))
+
+	s, err = a.ReadString("count", item)
+	assert.NoError(err)
+	assert.Equal("3", s)
+
+	i, err = a.ReadInt64("attrs.list[1]", item)
+	assert.NoError(err)
+	assert.Equal(int64(2), i)
+
+	b, err = a.ReadBool("attrs.color", item)
+	assert.True(errors.Is(err, ErrTypeMismatch))
+	assert.False(b)
+
+	// resolutions aren't shared between types nor accessors
+	s, err = ReadString("name", map[string]string{"name": "map"})
+	assert.NoError(err)
+	assert.Equal("map", s)
+
+	s, err = NewAccessor(WithTagNames(), WithNameMatcher(ExactMatcher)).ReadString("Name", item)
+	assert.NoError(err)
+	assert.Equal("foo", s)
+
+	_, err = NewAccessor(WithTagNames(), WithNameMatcher(ExactMatcher)).ReadString("name", item)
+	assert.True(errors.Is(err, ErrNotFound))
+}
+
+func TestTypedReadAllocations(t *testing.T) {
+	assert := assert.New(t)
+
+	item := newTypedItem()
+	var v interface{} = item
+	m := map[string]interface{}{"a": map[string]interface{}{"b": []interface{}{"c", 1, 2.5, true}}}
+	var mv interface{} = m
+	var nv interface{} = map[string]interface{}{"name": "bar"}
+	plain := NewAccessor(WithMethods(false))
+
+	cases := []struct {
+		name string
+		read func()
+	}{
+		{"string field", func() { ReadString("next.name", v) }},
+		{"embedded field", func() { ReadString("source", v) }},
+		{"pointer field", func() { ReadString("label", v) }},
+		{"map value", func() { ReadString("attrs.color", v) }},
+		{"slice element", func() { ReadString("tags[-1]", v) }},
+		{"int field", func() { ReadInt64("count", v) }},
+		{"float field", func() { ReadFloat64("price", v) }},
+		{"bool field", func() { ReadBool("active", v) }},
+		{"nested maps", func() { ReadString("a.b[0]", mv) }},
+		{"interface int", func() { ReadInt64("a.b[1]", mv) }},
+		{"interface float", func() { ReadFloat64("a.b[2]", mv) }},
+		{"interface bool", func() { ReadBool("a.b[3]", mv) }},
+		{"alternating types", func() { ReadString("name", v); ReadString("name", nv) }},
+		{"alternating accessors", func() { ReadString("name", v); plain.ReadString("name", v) }},
+	}
+
+	for _, c := range cases {
+		c.read()
+		assert.Equal(float64(0), testing.AllocsPerRun(100, c.read), c.name)
+	}
+
+	// segment keeps a resolution per accessor and type read by them
+	e, err := selectorCache.lookup("name")
+	if assert.NoError(err) {
+		found := map[*Accessor]int{}
+		for _, r := range *e.segments[0].resolved.Load() {
+			if r.typ == reflect.TypeOf(*item) {
+				found[r.accessor]++
+			}
+		}
+		assert.Equal(1, found[defaultAccessor()])
+		assert.Equal(1, found[plain])
+	}
+
+	s, err := ReadString("name", nv)
+	assert.NoError(err)
+	assert.Equal("bar", s)
+	s, err = plain.ReadString("name", v)
+	assert.NoError(err)
+	assert.Equal("foo", s)
+}
+
+func BenchmarkReadString(b *testing.B) {
+	item := newTypedItem()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ReadString("next.name", item)
+	}
+}
+
+func BenchmarkReadStringGeneric(b *testing.B) {
+	item := newTypedItem()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Read("next.name", item)
+	}
+}
@@ -0,0 +1,289 @@
+package access
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Wildcard is a path element matching every slice or array element, map key or struct field,
+// written as `[*]` or `.*`.
+type Wildcard struct{}
+
+// Match is a value found by ReadAll together with its concrete path.
+type Match struct {
+	Path  Path
+	Value interface{}
+}
+
+// MultiError collects errors of a write through wildcard path keyed by concrete path.
+type MultiError map[string]error
+
+func (e MultiError) Error() string {
+	paths := make([]string, 0, len(e))
+	for p := range e {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	msgs := make([]string, len(paths))
+	for i, p := range paths {
+		msgs[i] = fmt.Sprintf("`%s`: %s", p, e[p])
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns collected errors ordered by path.
+func (e MultiError) Unwrap() []error {
+	paths := make([]string, 0, len(e))
+	for p := range e {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	errs := make([]error, len(paths))
+	for i, p := range paths {
+		errs[i] = e[p]
+	}
+
+	return errs
+}
+
+func ReadAll(s interface{}, v interface{}) ([]Match, error) {
+	return defaultAccessor().ReadAll(s, v)
+}
+
+// ReadAll reads every location matched by path. Locations missing below a wildcard are skipped.
+func (path Path) ReadAll(v interface{}) ([]Match, error) {
+	return defaultAccessor().readAllPath(path, v)
+}
+
+func (a *Accessor) readAllPath(path Path, v interface{}) ([]Match, error) {
+
+	if err := a.checkDepth(path); err != nil {
+		return nil, err
+	}
+
+	rv := reflect.ValueOf(v)
+
+	paths, err := path.expand(a, rv)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []Match{}
+
+	for _, p := range paths {
+		re, err := p.read(a, rv)
+
+		// path without wildcards is returned as is, so matches get a copy of it
+		if path.multiIndex() < 0 {
+			p = append(Path{}, p...)
+		}
+
+		if err != nil {
+			if path.multiIndex() < 0 {
+				return nil, err
+			}
+			continue
+		}
+
+		var val interface{}
+		if re.IsValid() {
+			val = re.Interface()
+		}
+
+		matches = append(matches, Match{p, val})
+	}
+
+	return matches, nil
+}
+
+func (p Path) writeAll(a *Accessor, v reflect.Value, w reflect.Value, wt reflect.Type) error {
+
+	paths, err := p.expand(a, v)
+	if err != nil {
+		return err
+	}
+
+	errs := MultiError{}
+
+	for _, cp := range paths {
+		if err := cp.write(a, v, w, wt); err != nil {
+			errs[cp.String()] = err
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// multiIndex returns position of the first element which may match several locations.
+func (p Path) multiIndex() int {
+	for i, e := range p {
+		switch e.(type) {
+		case Wildcard, Descent, Filter:
+			return i
+		}
+	}
+	return -1
+}
+
+// checkSingle fails with error matching ErrUnsupportedKind when p may match several locations,
+// as those are only read by ReadAll.
+func (p Path) checkSingle() error {
+
+	i := p.multiIndex()
+	if i < 0 {
+		return nil
+	}
+
+	return Error{kindError{fmt.Errorf("Path element `%s` may match several locations, read it with ReadAll", p[i:i+1]), ErrUnsupportedKind}, append([]interface{}{}, p[:i]...)}
+}
+
+// expand resolves wildcards and recursive descents of p against v into concrete paths.
+func (p Path) expand(a *Accessor, v reflect.Value) ([]Path, error) {
+
+	i := p.multiIndex()
+
+	if i < 0 {
+		return []Path{p}, nil
+	}
+
+	head := p[:i]
+
+	hv, err := head.read(a, v)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		prefixes []Path
+		values   []reflect.Value
+	)
+
+	switch f := p[i].(type) {
+	case Descent:
+		prefixes, values = descendants(a, hv, p[i+1:])
+	case Wildcard:
+		keys, vals, err := elements(a, hv)
+		if err != nil {
+			return nil, Error{err, append([]interface{}{}, head...)}
+		}
+		for _, key := range keys {
+			prefixes = append(prefixes, Path{key})
+		}
+		values = vals
+	case Filter:
+		keys, vals, err := elements(a, hv)
+		if err != nil {
+			return nil, Error{err, append([]interface{}{}, head...)}
+		}
+		for n, key := range keys {
+			if f.match(a, vals[n]) {
+				prefixes = append(prefixes, Path{key})
+				values = append(values, vals[n])
+			}
+		}
+	}
+
+	paths := []Path{}
+
+	for n, prefix := range prefixes {
+
+		tails, err := p[i+1:].expand(a, values[n])
+		if err != nil {
+			// locations missing below a wildcard are skipped
+			continue
+		}
+
+		for _, tail := range tails {
+			cp := make(Path, 0, len(head)+len(prefix)+len(tail))
+			cp = append(cp, head...)
+			cp = append(cp, prefix...)
+			cp = append(cp, tail...)
+			paths = append(paths, cp)
+		}
+	}
+
+	return paths, nil
+}
+
+// elements lists path elements and values of every child of v.
+func elements(a *Accessor, v reflect.Value) ([]interface{}, []reflect.Value, error) {
+
+	if !v.IsValid() {
+		return nil, nil, kindError{fmt.Errorf("struct, map, slice, array or IndexReader with Len() expected"), ErrNotFound}
+	}
+
+	v = indirectRead(v, indexReaderInterface)
+
+	if r, ok := v.Interface().(IndexReader); ok {
+		if l, ok := r.(Lener); ok {
+			keys := make([]interface{}, l.Len())
+			values := make([]reflect.Value, l.Len())
+			for i := range keys {
+				val, err := r.Index(i)
+				if err != nil {
+					return nil, nil, err
+				}
+				keys[i] = i
+				values[i] = reflect.ValueOf(val)
+			}
+			return keys, values, nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			break
+		}
+		return elements(a, v.Elem())
+
+	case reflect.Slice, reflect.Array:
+		keys := make([]interface{}, v.Len())
+		values := make([]reflect.Value, v.Len())
+		for i := range keys {
+			keys[i] = i
+			values[i] = v.Index(i)
+		}
+		return keys, values, nil
+
+	case reflect.Map:
+		if kk := v.Type().Key().Kind(); kk != reflect.String {
+			return nil, nil, kindError{fmt.Errorf("Map key type is not a string"), ErrUnsupportedKind}
+		}
+
+		names := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			names = append(names, k.String())
+		}
+		sort.Strings(names)
+
+		keys := make([]interface{}, len(names))
+		values := make([]reflect.Value, len(names))
+		for i, name := range names {
+			keys[i] = name
+			values[i] = v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		}
+		return keys, values, nil
+
+	case reflect.Struct:
+		fields := structElements(a, v.Type())
+		keys := make([]interface{}, len(fields))
+		values := make([]reflect.Value, len(fields))
+		for i, f := range fields {
+			keys[i] = f.name
+			values[i] = v.Field(f.index)
+		}
+		return keys, values, nil
+	}
+
+	return nil, nil, kindError{fmt.Errorf("struct, map, slice, array or IndexReader with Len() expected"), ErrUnsupportedKind}
+}
@@ -0,0 +1,146 @@
+package access
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type wildcardUser struct {
+	Email  string
+	Active bool
+	secret string
+}
+
+func TestWildcardParse(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(Path{"users", Wildcard{}, "email"}, New("users[*].email"))
+	assert.Equal(Path{"config", Wildcard{}, "enabled"}, New("config.*.enabled"))
+	assert.Equal(Path{Wildcard{}, "a"}, New("*.a"))
+	assert.Equal("users[*].email", New("users.*.email").String())
+
+	_, err := Read("users[*]", map[string]interface{}{})
+	assert.Error(err)
+}
+
+func TestReadMultiple(t *testing.T) {
+	assert := assert.New(t)
+
+	data := map[string]interface{}{
+		"users": []interface{}{map[string]interface{}{"id": 1, "active": true}},
+	}
+
+	cases := []struct {
+		selector string
+		path     Path
+	}{
+		{"users[*].id", Path{"users"}},
+		{"users..id", Path{"users"}},
+		{"..id", Path{}},
+		{"users[?(@.active)].id", Path{"users"}},
+	}
+
+	// paths which may match several locations are rejected even when they match one
+	for _, c := range cases {
+		_, err := Read(c.selector, data)
+		assert.True(errors.Is(err, ErrUnsupportedKind), c.selector)
+		assert.Equal(c.path, Path(err.(Error).Path), c.selector)
+
+		_, _, err = Lookup(c.selector, data)
+		assert.True(errors.Is(err, ErrUnsupportedKind), c.selector)
+		assert.False(Exists(c.selector, data), c.selector)
+		assert.Nil(MustRead(c.selector, data), c.selector)
+
+		matches, err := ReadAll(c.selector, data)
+		assert.NoError(err, c.selector)
+		assert.Equal([]Match{{Path{"users", 0, "id"}, 1}}, matches, c.selector)
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	assert := assert.New(t)
+
+	data := map[string]interface{}{
+		"users": []wildcardUser{
+			{"a@example.com", true, "x"},
+			{"b@example.com", false, "y"},
+		},
+		"config": map[string]interface{}{
+			"feature": map[string]interface{}{"enabled": true},
+			"other":   map[string]interface{}{"enabled": false},
+			"broken":  map[string]interface{}{},
+		},
+		"indexes": LenIndexes{Indexes{[]string{"foo", "bar"}}},
+	}
+
+	matches, err := ReadAll("users[*].email", data)
+	assert.NoError(err)
+	assert.Equal([]Match{
+		{Path{"users", 0, "email"}, "a@example.com"},
+		{Path{"users", 1, "email"}, "b@example.com"},
+	}, matches)
+
+	//missing locations are skipped, map keys are sorted
+	matches, err = ReadAll("config.*.enabled", data)
+	assert.NoError(err)
+	assert.Equal([]Match{
+		{Path{"config", "feature", "enabled"}, true},
+		{Path{"config", "other", "enabled"}, false},
+	}, matches)
+
+	//struct fields, unexported are hidden
+	matches, err = ReadAll("users[0].*", data)
+	assert.NoError(err)
+	assert.Equal([]Match{
+		{Path{"users", 0, "Email"}, "a@example.com"},
+		{Path{"users", 0, "Active"}, true},
+	}, matches)
+
+	//IndexReader with Len
+	matches, err = ReadAll("indexes[*]", &data)
+	assert.NoError(err)
+	assert.Equal([]Match{{Path{"indexes", 0}, "foo"}, {Path{"indexes", 1}, "bar"}}, matches)
+
+	//nested wildcards
+	matches, err = ReadAll("*[*].active", data)
+	assert.NoError(err)
+	assert.Len(matches, 2)
+
+	//path without wildcard
+	matches, err = ReadAll("users[1].email", data)
+	assert.NoError(err)
+	assert.Equal([]Match{{Path{"users", 1, "email"}, "b@example.com"}}, matches)
+
+	_, err = ReadAll("missing[*]", data)
+	assert.Error(err)
+
+	_, err = ReadAll("users[0].email[*]", data)
+	assert.Error(err)
+}
+
+func TestWriteAll(t *testing.T) {
+	assert := assert.New(t)
+
+	users := []wildcardUser{{Email: "a@example.com"}, {Email: "b@example.com"}}
+
+	assert.NoError(Write("[*].active", &users, true))
+	assert.True(users[0].Active)
+	assert.True(users[1].Active)
+
+	config := map[string]interface{}{
+		"feature": map[string]interface{}{"enabled": true},
+		"other":   map[string]interface{}{},
+		"broken":  "string",
+	}
+
+	err := Write("*.enabled", &config, false)
+	assert.Equal(false, MustRead("feature.enabled", config))
+	assert.Equal(false, MustRead("other.enabled", config))
+
+	if assert.IsType(MultiError{}, err) {
+		errs := err.(MultiError)
+		assert.Len(errs, 1)
+		assert.Error(errs["broken.enabled"])
+	}
+}